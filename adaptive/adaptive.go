@@ -0,0 +1,226 @@
+// Package adaptive chooses the category and the difficulty of the next
+// question of a player from its answers, so the questions are neither too
+// easy nor too hard.
+//
+// Each player has an Elo rating, overall and per category, which goes up
+// with correct answers and down with wrong ones, more so for surprising
+// results. The questions are rated by difficulty:
+//
+//	s := adaptive.NewSelector(adaptive.Target(adaptive.DefaultSuccess))
+//
+//	q, err := s.Random(client.Question, "ana")
+//	...
+//	profile := s.Answer("ana", &q, answer)
+package adaptive
+
+import (
+	"html"
+	"math"
+	"sync"
+
+	"github.com/pinheirolucas/opentrivia"
+	"github.com/pinheirolucas/opentrivia/stats"
+)
+
+// InitialRating is the rating of a new player.
+const InitialRating = 1500
+
+// DefaultK is the maximum change of a rating after an answer.
+const DefaultK = 32
+
+// DefaultDifficultyRatings are the ratings of the questions of each
+// difficulty.
+var DefaultDifficultyRatings = map[opentrivia.QuestionDifficulty]float64{
+	opentrivia.QuestionDifficultyEasy:   1300,
+	opentrivia.QuestionDifficultyMedium: 1500,
+	opentrivia.QuestionDifficultyHard:   1700,
+}
+
+// Expected returns the chance of a correct answer by a player with the
+// rating to a question with the question rating.
+func Expected(rating, question float64) float64 {
+	return 1 / (1 + math.Pow(10, (question-rating)/400))
+}
+
+// Rating is the rating of a player and the number of answers it is based
+// on.
+type Rating struct {
+	Value    float64 `json:"value"`
+	Answered int     `json:"answered"`
+}
+
+// Profile holds the ratings of a player.
+type Profile struct {
+	PlayerID   string                                 `json:"player_id"`
+	Overall    Rating                                 `json:"overall"`
+	Categories map[opentrivia.QuestionCategory]Rating `json:"categories"`
+}
+
+// Category returns the rating of the player in the category. A category
+// never answered has the overall rating of the player.
+func (p Profile) Category(c opentrivia.QuestionCategory) Rating {
+	if r, ok := p.Categories[c]; ok {
+		return r
+	}
+
+	return Rating{Value: p.Overall.Value}
+}
+
+func (p Profile) copy() Profile {
+	categories := make(map[opentrivia.QuestionCategory]Rating, len(p.Categories))
+	for c, r := range p.Categories {
+		categories[c] = r
+	}
+	p.Categories = categories
+
+	return p
+}
+
+// RandomSource provides single random questions. It is implemented by
+// *opentrivia.QuestionService.
+type RandomSource interface {
+	Random(options *opentrivia.QuestionRandomOptions) (opentrivia.Question, error)
+}
+
+// Selector rates the players and chooses their next questions. It is safe
+// for concurrent use.
+//
+// The fields of a Selector must not be changed after it is first used.
+type Selector struct {
+	// K is the maximum change of a rating after an answer. If zero,
+	// DefaultK is used.
+	K float64
+
+	// DifficultyRatings are the ratings of the questions of each
+	// difficulty. If nil, DefaultDifficultyRatings is used.
+	DifficultyRatings map[opentrivia.QuestionDifficulty]float64
+
+	strategy Strategy
+
+	mu       sync.Mutex
+	profiles map[string]*Profile
+}
+
+// NewSelector returns a selector choosing the questions with the strategy.
+// If strategy is nil, Target(DefaultSuccess) is used.
+func NewSelector(strategy Strategy) *Selector {
+	if strategy == nil {
+		strategy = Target(DefaultSuccess)
+	}
+
+	return &Selector{
+		strategy: strategy,
+		profiles: make(map[string]*Profile),
+	}
+}
+
+func (s *Selector) k() float64 {
+	if s.K == 0 {
+		return DefaultK
+	}
+
+	return s.K
+}
+
+func (s *Selector) difficulties() map[opentrivia.QuestionDifficulty]float64 {
+	if s.DifficultyRatings == nil {
+		return DefaultDifficultyRatings
+	}
+
+	return s.DifficultyRatings
+}
+
+// profile returns the profile of the player, creating it if needed. It must
+// be called with s.mu held.
+func (s *Selector) profile(playerID string) *Profile {
+	p, ok := s.profiles[playerID]
+	if !ok {
+		p = &Profile{
+			PlayerID:   playerID,
+			Overall:    Rating{Value: InitialRating},
+			Categories: make(map[opentrivia.QuestionCategory]Rating),
+		}
+		s.profiles[playerID] = p
+	}
+
+	return p
+}
+
+// Profile returns the current ratings of the player.
+func (s *Selector) Profile(playerID string) Profile {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.profile(playerID).copy()
+}
+
+// Update rates an answer of the player to a question of the category and
+// difficulty, and returns the new ratings. A zero category updates only the
+// overall rating.
+func (s *Selector) Update(playerID string, category opentrivia.QuestionCategory, difficulty opentrivia.QuestionDifficulty, correct bool) Profile {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	question, ok := s.difficulties()[difficulty]
+	if !ok {
+		question = InitialRating
+	}
+
+	score := 0.0
+	if correct {
+		score = 1
+	}
+
+	p := s.profile(playerID)
+	if category != 0 {
+		r := p.Category(category)
+		r.Value += s.k() * (score - Expected(r.Value, question))
+		r.Answered++
+		p.Categories[category] = r
+	}
+
+	p.Overall.Value += s.k() * (score - Expected(p.Overall.Value, question))
+	p.Overall.Answered++
+
+	return p.copy()
+}
+
+// Answer rates the answer of the player to the question.
+func (s *Selector) Answer(playerID string, q *opentrivia.Question, answer string) Profile {
+	category, _ := opentrivia.ParseQuestionCategory(html.UnescapeString(q.Category))
+
+	return s.Update(playerID, category, opentrivia.QuestionDifficulty(q.Difficulty), q.IsAnswerCorrect(answer))
+}
+
+// Replay rates the answers of a history, such as the records of the stats
+// package, in order.
+func (s *Selector) Replay(records []stats.Record) {
+	for _, r := range records {
+		s.Update(r.PlayerID, r.Category, r.Difficulty, r.Correct)
+	}
+}
+
+// Next returns the options of the next question of the player.
+func (s *Selector) Next(playerID string) *opentrivia.QuestionRandomOptions {
+	p := s.Profile(playerID)
+	c := s.strategy.Choose(p, s.difficulties())
+
+	return &opentrivia.QuestionRandomOptions{
+		Category:   c.Category,
+		Difficulty: c.Difficulty,
+	}
+}
+
+// Random returns the next question of the player from the source. When the
+// chosen category has no questions left, any category is tried.
+func (s *Selector) Random(source RandomSource, playerID string) (opentrivia.Question, error) {
+	options := s.Next(playerID)
+
+	q, err := source.Random(options)
+	if err == opentrivia.ErrNoResults && options.Category != 0 {
+		options.Category = 0
+		return source.Random(options)
+	}
+
+	return q, err
+}
@@ -0,0 +1,96 @@
+package adaptive
+
+import (
+	"math"
+
+	"github.com/pinheirolucas/opentrivia"
+)
+
+// Choice is the category and the difficulty of the next question. A zero
+// category or an empty difficulty leave the choice to the API.
+type Choice struct {
+	Category   opentrivia.QuestionCategory
+	Difficulty opentrivia.QuestionDifficulty
+}
+
+// Strategy chooses the next question of a player from its profile.
+type Strategy interface {
+	// Choose returns the choice for the player. The difficulties are the
+	// ratings of the questions of each difficulty.
+	Choose(p Profile, difficulties map[opentrivia.QuestionDifficulty]float64) Choice
+}
+
+// StrategyFunc is an adapter to allow the use of ordinary functions as a
+// Strategy.
+type StrategyFunc func(p Profile, difficulties map[opentrivia.QuestionDifficulty]float64) Choice
+
+// Choose calls f(p, difficulties).
+func (f StrategyFunc) Choose(p Profile, difficulties map[opentrivia.QuestionDifficulty]float64) Choice {
+	return f(p, difficulties)
+}
+
+// DefaultSuccess is the chance of a correct answer targeted by the
+// default strategy.
+const DefaultSuccess = 0.7
+
+// Target returns a strategy that keeps the category open and chooses the
+// difficulty whose chance of a correct answer is the closest to success.
+func Target(success float64) Strategy {
+	return StrategyFunc(func(p Profile, difficulties map[opentrivia.QuestionDifficulty]float64) Choice {
+		return Choice{Difficulty: closest(p.Overall.Value, success, difficulties)}
+	})
+}
+
+// Weakest returns a strategy that chooses the category with the lowest
+// rating among the provided ones, to train the player where it is weaker,
+// and the difficulty whose chance of a correct answer is the closest to
+// success in that category. The categories never answered are rated as
+// the player.
+func Weakest(success float64, categories ...opentrivia.QuestionCategory) Strategy {
+	return StrategyFunc(func(p Profile, difficulties map[opentrivia.QuestionDifficulty]float64) Choice {
+		if len(categories) == 0 {
+			return Choice{Difficulty: closest(p.Overall.Value, success, difficulties)}
+		}
+
+		weakest := categories[0]
+		for _, c := range categories[1:] {
+			if p.Category(c).Value < p.Category(weakest).Value {
+				weakest = c
+			}
+		}
+
+		return Choice{
+			Category:   weakest,
+			Difficulty: closest(p.Category(weakest).Value, success, difficulties),
+		}
+	})
+}
+
+// closest returns the difficulty whose chance of a correct answer by a
+// player with the rating is the closest to success. Ties go to the easier
+// difficulty.
+func closest(rating, success float64, difficulties map[opentrivia.QuestionDifficulty]float64) opentrivia.QuestionDifficulty {
+	var (
+		best     opentrivia.QuestionDifficulty
+		bestDiff = math.Inf(1)
+	)
+
+	// The difficulties are visited in order, so the result does not depend
+	// on the order of the map.
+	for _, d := range []opentrivia.QuestionDifficulty{
+		opentrivia.QuestionDifficultyEasy,
+		opentrivia.QuestionDifficultyMedium,
+		opentrivia.QuestionDifficultyHard,
+	} {
+		q, ok := difficulties[d]
+		if !ok {
+			continue
+		}
+
+		if diff := math.Abs(Expected(rating, q) - success); diff < bestDiff {
+			best, bestDiff = d, diff
+		}
+	}
+
+	return best
+}
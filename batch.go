@@ -0,0 +1,111 @@
+package opentrivia
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// DefaultBatchConcurrency is the number of requests sent at once by
+// ListBatch when no concurrency is provided.
+const DefaultBatchConcurrency = 4
+
+// DefaultBatchRateLimit spaces the requests of ListBatch when the client
+// has no rate limit. It is the rate limit of the Open Trivia API, which
+// allows a request every 5 seconds for each IP. It only applies to the
+// batches: the other requests of the client are not spaced from them.
+const DefaultBatchRateLimit = 5 * time.Second
+
+// BatchResult is the result of an item of a batch.
+type BatchResult struct {
+	Questions []Question
+	Err       error
+}
+
+// ListBatch lists the questions of each options, sending up to concurrency
+// requests at once, and returns the results in the order of the options.
+// Each item fails on its own: the error of an item is set in its result.
+//
+// The requests honor the rate limit of the client, shared by all the items,
+// and the context. Once the context is done, the items not yet sent fail
+// with its error. A client created without WithRateLimit spaces the
+// requests of its batches by DefaultBatchRateLimit, so the items do not
+// fail with ErrRateLimit. That limit is shared by the batches of the client
+// only, so a request sent with List during a batch may still exceed the
+// rate limit of the Open Trivia API; WithRateLimit spaces all the requests
+// of the client instead. WithRateLimit(0) disables it, such as for a
+// self-hosted server.
+//
+// The options are copied, so they are not modified and may be shared by
+// the items. A nil options uses the defaults of List.
+func (q *QuestionService) ListBatch(ctx context.Context, options []*QuestionListOptions, concurrency int) []BatchResult {
+	if concurrency <= 0 {
+		concurrency = DefaultBatchConcurrency
+	}
+
+	if q.client.limiter == nil {
+		ctx = context.WithValue(ctx, batchLimiterKey{}, q.client.batchLimiter)
+	}
+
+	ctx, span := q.client.tracer().Start(ctx, SpanQuestionListBatch)
+	span.SetAttribute("opentrivia.items", len(options))
+	span.SetAttribute("opentrivia.concurrency", concurrency)
+	defer span.End()
+
+	results := make([]BatchResult, len(options))
+	items := make(chan int)
+
+	var wg sync.WaitGroup
+	for w := 0; w < concurrency && w < len(options); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for i := range items {
+				results[i] = q.listItem(ctx, options[i])
+			}
+		}()
+	}
+
+	for i := range options {
+		select {
+		case items <- i:
+		case <-ctx.Done():
+			results[i] = BatchResult{Questions: []Question{}, Err: ctx.Err()}
+		}
+	}
+	close(items)
+
+	wg.Wait()
+
+	return results
+}
+
+func (q *QuestionService) listItem(ctx context.Context, options *QuestionListOptions) BatchResult {
+	if err := ctx.Err(); err != nil {
+		return BatchResult{Questions: []Question{}, Err: err}
+	}
+
+	var copied *QuestionListOptions
+	if options != nil {
+		o := *options
+		copied = &o
+	}
+
+	questions, err := q.ListContext(ctx, copied)
+	return BatchResult{Questions: questions, Err: err}
+}
+
+// batchLimiterKey is the context key of the limiter of a batch.
+type batchLimiterKey struct{}
+
+// requestLimiter returns the limiter of a request: the rate limit of the
+// client, or the limiter of the batch sending it.
+func (c *Client) requestLimiter(ctx context.Context) *limiter {
+	if c.limiter != nil {
+		return c.limiter
+	}
+
+	l, _ := ctx.Value(batchLimiterKey{}).(*limiter)
+	return l
+}
@@ -0,0 +1,399 @@
+// Package bot runs trivia in chat rooms. It is platform agnostic: a
+// Transport carries the messages between the chat and the Bot, which
+// dispatches the commands:
+//
+//	!trivia [category]  asks a question in the channel
+//	!answer B           answers with the letter or the text of a choice
+//	!skip               reveals the answer and closes the question
+//	!score              shows the scores of the channel
+//	!categories         lists the categories
+//	!help               lists the commands
+//
+// Reactions, such as the regional indicator emojis of Discord or Slack,
+// answer with the matching letter.
+//
+// Each user answers a question once. The question is closed by a correct
+// answer, by !skip, once every player of the channel answered it, or once
+// it has been open for the Timeout of the Bot.
+package bot
+
+import (
+	"fmt"
+	"html"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pinheirolucas/opentrivia"
+)
+
+// DefaultPrefix is the prefix of the commands.
+const DefaultPrefix = "!"
+
+// DefaultTimeout is the time a question stays open when the Bot has no
+// Timeout.
+const DefaultTimeout = time.Minute
+
+// Points are the points given to a correct answer of each difficulty.
+var Points = map[string]int{
+	string(opentrivia.QuestionDifficultyEasy):   1,
+	string(opentrivia.QuestionDifficultyMedium): 2,
+	string(opentrivia.QuestionDifficultyHard):   3,
+}
+
+// Message is a message received from a chat. Reaction is set, instead of
+// Text, when the user reacted to the question.
+type Message struct {
+	Channel  string
+	User     string
+	Text     string
+	Reaction string
+}
+
+// question is the question open in a channel.
+type question struct {
+	question opentrivia.Question
+	answers  []string
+	answered map[string]bool
+	asked    time.Time
+}
+
+// Bot dispatches the commands received from the chats. It is safe for
+// concurrent use.
+type Bot struct {
+	// Prefix is the prefix of the commands. If empty, DefaultPrefix is
+	// used.
+	Prefix string
+
+	// Timeout is the time a question stays open. Once it is over, the
+	// answer is revealed by the next command of the channel. If zero,
+	// DefaultTimeout is used.
+	Timeout time.Duration
+
+	// Now returns the current time. If nil, time.Now is used.
+	Now func() time.Time
+
+	client *opentrivia.Client
+
+	mu        sync.Mutex
+	questions map[string]*question
+	scores    map[string]map[string]int
+
+	// players holds the users who sent a command to each channel.
+	players map[string]map[string]bool
+}
+
+// New returns a bot asking the questions of the client.
+func New(client *opentrivia.Client) *Bot {
+	return &Bot{
+		client:    client,
+		questions: make(map[string]*question),
+		scores:    make(map[string]map[string]int),
+		players:   make(map[string]map[string]bool),
+	}
+}
+
+func (b *Bot) prefix() string {
+	if b.Prefix == "" {
+		return DefaultPrefix
+	}
+
+	return b.Prefix
+}
+
+func (b *Bot) now() time.Time {
+	if b.Now == nil {
+		return time.Now()
+	}
+
+	return b.Now()
+}
+
+func (b *Bot) timeout() time.Duration {
+	if b.Timeout <= 0 {
+		return DefaultTimeout
+	}
+
+	return b.Timeout
+}
+
+// Handle dispatches the message and returns the replies to send to its
+// channel. Messages that are not commands have no replies.
+func (b *Bot) Handle(m Message) []string {
+	if m.Reaction != "" {
+		letter, ok := ReactionLetter(m.Reaction)
+		if !ok {
+			return nil
+		}
+
+		b.join(m)
+		return b.answer(m, string(letter))
+	}
+
+	text := strings.TrimSpace(m.Text)
+	if !strings.HasPrefix(text, b.prefix()) {
+		return nil
+	}
+
+	fields := strings.Fields(strings.TrimPrefix(text, b.prefix()))
+	if len(fields) == 0 {
+		return nil
+	}
+	args := strings.TrimSpace(strings.TrimPrefix(strings.TrimPrefix(text, b.prefix()), fields[0]))
+
+	b.join(m)
+
+	switch strings.ToLower(fields[0]) {
+	case "trivia":
+		return b.trivia(m, args)
+	case "answer", "a":
+		return b.answer(m, args)
+	case "skip", "reveal":
+		return b.skip(m)
+	case "score", "scores":
+		return b.score(m)
+	case "categories":
+		return b.categories()
+	case "help":
+		return b.help()
+	}
+
+	return []string{fmt.Sprintf("Unknown command %q. Try %shelp.", fields[0], b.prefix())}
+}
+
+// join records the user as a player of the channel.
+func (b *Bot) join(m Message) {
+	if m.User == "" {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	players, ok := b.players[m.Channel]
+	if !ok {
+		players = make(map[string]bool)
+		b.players[m.Channel] = players
+	}
+	players[m.User] = true
+}
+
+// expire closes the question of the channel if its time is over, and
+// returns the reply revealing its answer. It must be called with b.mu held.
+func (b *Bot) expire(channel string) []string {
+	open, ok := b.questions[channel]
+	if !ok || b.now().Sub(open.asked) < b.timeout() {
+		return nil
+	}
+
+	delete(b.questions, channel)
+
+	return []string{"Time is up! The answer was " + html.UnescapeString(open.question.CorrectAnswer) + "."}
+}
+
+func (b *Bot) trivia(m Message, args string) []string {
+	b.mu.Lock()
+	replies := b.expire(m.Channel)
+	open, ok := b.questions[m.Channel]
+	b.mu.Unlock()
+
+	if ok {
+		return append([]string{"There is already an open question:"}, formatQuestion(open)...)
+	}
+
+	options := &opentrivia.QuestionRandomOptions{}
+	if args != "" {
+		c, err := opentrivia.ParseQuestionCategory(args)
+		if err != nil {
+			return []string{fmt.Sprintf("Unknown category %q. Try %scategories.", args, b.prefix())}
+		}
+
+		options.Category = c
+		options.AllowUnknownCategory = true
+	}
+
+	q, err := b.client.Question.Random(options)
+	if err != nil {
+		return append(replies, fmt.Sprintf("Could not get a question: %s", err))
+	}
+
+	open = &question{
+		question: q,
+		answers:  q.ShuffleAnswers(),
+		answered: make(map[string]bool),
+		asked:    b.now(),
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	// Another question may have been opened while this one was fetched.
+	if current, ok := b.questions[m.Channel]; ok {
+		return append([]string{"There is already an open question:"}, formatQuestion(current)...)
+	}
+	b.questions[m.Channel] = open
+
+	return append(replies, formatQuestion(open)...)
+}
+
+func (b *Bot) answer(m Message, args string) []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if replies := b.expire(m.Channel); replies != nil {
+		return replies
+	}
+
+	open, ok := b.questions[m.Channel]
+	if !ok {
+		return []string{fmt.Sprintf("There is no open question. Try %strivia.", b.prefix())}
+	}
+	if args == "" {
+		return []string{fmt.Sprintf("Usage: %sanswer B", b.prefix())}
+	}
+	if open.answered[m.User] {
+		return []string{fmt.Sprintf("%s, you already answered this question.", m.User)}
+	}
+
+	open.answered[m.User] = true
+
+	answer := opentrivia.ChooseAnswer(open.answers, args)
+	if !open.question.IsAnswerCorrect(answer) {
+		replies := []string{fmt.Sprintf("%s, that is not it.", m.User)}
+
+		for p := range b.players[m.Channel] {
+			if !open.answered[p] {
+				return replies
+			}
+		}
+
+		delete(b.questions, m.Channel)
+
+		return append(replies, "Everyone answered. The answer was "+html.UnescapeString(open.question.CorrectAnswer)+".")
+	}
+
+	points := Points[open.question.Difficulty]
+	if points == 0 {
+		points = 1
+	}
+
+	scores, ok := b.scores[m.Channel]
+	if !ok {
+		scores = make(map[string]int)
+		b.scores[m.Channel] = scores
+	}
+	scores[m.User] += points
+
+	delete(b.questions, m.Channel)
+
+	return []string{fmt.Sprintf("%s got it! The answer was %s. +%d (%d points)",
+		m.User, html.UnescapeString(open.question.CorrectAnswer), points, scores[m.User])}
+}
+
+func (b *Bot) skip(m Message) []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if replies := b.expire(m.Channel); replies != nil {
+		return replies
+	}
+
+	open, ok := b.questions[m.Channel]
+	if !ok {
+		return []string{fmt.Sprintf("There is no open question. Try %strivia.", b.prefix())}
+	}
+
+	delete(b.questions, m.Channel)
+
+	return []string{"Skipped. The answer was " + html.UnescapeString(open.question.CorrectAnswer) + "."}
+}
+
+func (b *Bot) score(m Message) []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	scores := b.scores[m.Channel]
+	if len(scores) == 0 {
+		return []string{"No scores yet."}
+	}
+
+	users := make([]string, 0, len(scores))
+	for u := range scores {
+		users = append(users, u)
+	}
+	sort.Slice(users, func(i, j int) bool {
+		if scores[users[i]] != scores[users[j]] {
+			return scores[users[i]] > scores[users[j]]
+		}
+
+		return users[i] < users[j]
+	})
+
+	lines := []string{"Scores:"}
+	for i, u := range users {
+		lines = append(lines, fmt.Sprintf("%d. %s %d", i+1, u, scores[u]))
+	}
+
+	return lines
+}
+
+func (b *Bot) categories() []string {
+	categories, err := b.client.Category.List()
+	if err != nil {
+		return []string{fmt.Sprintf("Could not list the categories: %s", err)}
+	}
+
+	names := make([]string, len(categories))
+	for i, c := range categories {
+		names[i] = fmt.Sprintf("%d %s", c.ID, html.UnescapeString(c.Name))
+	}
+
+	return []string{"Categories: " + strings.Join(names, ", ")}
+}
+
+func (b *Bot) help() []string {
+	p := b.prefix()
+
+	return []string{
+		p + "trivia [category]: ask a question",
+		p + "answer B: answer with the letter or the text of a choice",
+		p + "skip: reveal the answer and close the question",
+		p + "score: show the scores",
+		p + "categories: list the categories",
+	}
+}
+
+func formatQuestion(q *question) []string {
+	lines := []string{fmt.Sprintf("[%s, %s] %s",
+		html.UnescapeString(q.question.Category),
+		q.question.Difficulty,
+		html.UnescapeString(q.question.Question),
+	)}
+
+	for i, a := range q.answers {
+		lines = append(lines, fmt.Sprintf("%c) %s", 'A'+i, html.UnescapeString(a)))
+	}
+
+	return lines
+}
+
+// ReactionLetter returns the letter of the choice matching a reaction: a
+// regional indicator emoji, such as 🇧, its Slack or Discord name, such as
+// :regional_indicator_b:, or the letter itself.
+func ReactionLetter(reaction string) (byte, bool) {
+	r := strings.ToLower(strings.Trim(strings.TrimSpace(reaction), ":"))
+	r = strings.TrimPrefix(r, "regional_indicator_")
+	r = strings.TrimPrefix(r, "letter_")
+
+	if len(r) == 1 && r[0] >= 'a' && r[0] <= 'z' {
+		return r[0] - 'a' + 'A', true
+	}
+
+	runes := []rune(r)
+	if len(runes) == 1 && runes[0] >= '\U0001F1E6' && runes[0] <= '\U0001F1FF' {
+		return byte(runes[0]-'\U0001F1E6') + 'A', true
+	}
+
+	return 0, false
+}
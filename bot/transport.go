@@ -0,0 +1,171 @@
+package bot
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// Transport carries the messages between a chat and a Bot.
+type Transport interface {
+	// Receive blocks until the next message. It returns io.EOF when there
+	// are no more messages.
+	Receive() (Message, error)
+
+	// Send sends a line of text to the channel.
+	Send(channel, text string) error
+}
+
+// Serve dispatches the messages received from the transport and sends the
+// replies until the transport returns io.EOF.
+func (b *Bot) Serve(t Transport) error {
+	for {
+		m, err := t.Receive()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		for _, reply := range b.Handle(m) {
+			if err := t.Send(m.Channel, reply); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// LineTransport is a Transport speaking an IRC-style line protocol. It
+// receives lines such as:
+//
+//	:ana PRIVMSG #trivia :!answer B
+//	:ana REACT #trivia :regional_indicator_b
+//	PING :server
+//
+// and sends lines such as:
+//
+//	PRIVMSG #trivia :ana got it!
+//
+// It is answered with PONG. Other lines are ignored.
+type LineTransport struct {
+	scanner *bufio.Scanner
+
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewLineTransport returns a LineTransport reading from r and writing to w.
+func NewLineTransport(r io.Reader, w io.Writer) *LineTransport {
+	return &LineTransport{scanner: bufio.NewScanner(r), w: w}
+}
+
+// Receive implements the Transport interface.
+func (t *LineTransport) Receive() (Message, error) {
+	for t.scanner.Scan() {
+		line := strings.TrimRight(t.scanner.Text(), "\r")
+
+		if strings.HasPrefix(line, "PING ") {
+			t.mu.Lock()
+			_, err := fmt.Fprintf(t.w, "PONG %s\r\n", strings.TrimPrefix(line, "PING "))
+			t.mu.Unlock()
+			if err != nil {
+				return Message{}, err
+			}
+
+			continue
+		}
+
+		if m, ok := parseLine(line); ok {
+			return m, nil
+		}
+	}
+
+	if err := t.scanner.Err(); err != nil {
+		return Message{}, err
+	}
+
+	return Message{}, io.EOF
+}
+
+// Send implements the Transport interface.
+func (t *LineTransport) Send(channel, text string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, line := range strings.Split(text, "\n") {
+		if _, err := fmt.Fprintf(t.w, "PRIVMSG %s :%s\r\n", channel, line); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// parseLine parses a line such as ":ana PRIVMSG #trivia :!trivia".
+func parseLine(line string) (Message, bool) {
+	if !strings.HasPrefix(line, ":") {
+		return Message{}, false
+	}
+
+	parts := strings.SplitN(line[1:], " ", 4)
+	if len(parts) != 4 || !strings.HasPrefix(parts[3], ":") {
+		return Message{}, false
+	}
+
+	// The user may be a full IRC prefix, such as ana!ana@host.
+	m := Message{
+		User:    strings.SplitN(parts[0], "!", 2)[0],
+		Channel: parts[2],
+	}
+
+	switch parts[1] {
+	case "PRIVMSG":
+		m.Text = parts[3][1:]
+	case "REACT":
+		m.Reaction = parts[3][1:]
+	default:
+		return Message{}, false
+	}
+
+	return m, true
+}
+
+// webhookMessage is the body of the webhook requests.
+type webhookMessage struct {
+	Channel  string `json:"channel"`
+	User     string `json:"user"`
+	Text     string `json:"text"`
+	Reaction string `json:"reaction"`
+}
+
+// WebhookHandler returns an http.Handler receiving the messages as JSON
+// POST requests, such as {"channel":"#trivia","user":"ana","text":"!score"},
+// and answering the replies as {"replies":["..."]}, for chats calling
+// outgoing webhooks.
+func (b *Bot) WebhookHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		var m webhookMessage
+		if err := json.NewDecoder(r.Body).Decode(&m); err != nil {
+			http.Error(w, "invalid message", http.StatusBadRequest)
+			return
+		}
+
+		replies := b.Handle(Message(m))
+		if replies == nil {
+			replies = []string{}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string][]string{"replies": replies})
+	})
+}
@@ -0,0 +1,112 @@
+package opentrivia
+
+import "github.com/google/go-querystring/query"
+
+const (
+	defaultCategoryRoute    = "api_category.php"
+	defaultCountRoute       = "api_count.php"
+	defaultGlobalCountRoute = "api_count_global.php"
+)
+
+// Category is the model of the Open Trivia API Category List method.
+type Category struct {
+	ID   QuestionCategory `json:"id"`
+	Name string           `json:"name"`
+}
+
+// CategoryCount is the number of verified questions of a category.
+type CategoryCount struct {
+	Category QuestionCategory `json:"category_id"`
+	Total    int              `json:"total_question_count"`
+	Easy     int              `json:"total_easy_question_count"`
+	Medium   int              `json:"total_medium_question_count"`
+	Hard     int              `json:"total_hard_question_count"`
+}
+
+// GlobalCount is the number of questions of the Open Trivia API.
+type GlobalCount struct {
+	Total    int `json:"total_num_of_questions"`
+	Pending  int `json:"total_num_of_pending_questions"`
+	Verified int `json:"total_num_of_verified_questions"`
+	Rejected int `json:"total_num_of_rejected_questions"`
+}
+
+type categoryCountOptions struct {
+	Category QuestionCategory `url:"category"`
+}
+
+type categoryResponse struct {
+	Categories []Category `json:"trivia_categories"`
+}
+
+type categoryCountResponse struct {
+	Category QuestionCategory `json:"category_id"`
+	Count    CategoryCount    `json:"category_question_count"`
+}
+
+type globalCountResponse struct {
+	Overall GlobalCount `json:"overall"`
+}
+
+// CategoryService handles communication with the category related
+// methods of the Open Trivia API.
+//
+// Ref.: https://opentdb.com/api_config.php
+type CategoryService service
+
+// List returns all the categories available on the Open Trivia API.
+func (c *CategoryService) List() ([]Category, error) {
+	req, err := c.client.NewRequest(defaultCategoryRoute, nil)
+	if err != nil {
+		return []Category{}, err
+	}
+
+	var resp categoryResponse
+	if _, err := c.client.Do(req, &resp); err != nil {
+		return []Category{}, err
+	}
+
+	return resp.Categories, nil
+}
+
+// Count returns the number of verified questions of the provided category.
+func (c *CategoryService) Count(category QuestionCategory) (CategoryCount, error) {
+	options := &categoryCountOptions{
+		Category: category,
+	}
+
+	v, err := query.Values(options)
+	if err != nil {
+		return CategoryCount{}, err
+	}
+
+	req, err := c.client.NewRequest(defaultCountRoute, v)
+	if err != nil {
+		return CategoryCount{}, err
+	}
+
+	var resp categoryCountResponse
+	if _, err := c.client.Do(req, &resp); err != nil {
+		return CategoryCount{}, err
+	}
+
+	count := resp.Count
+	count.Category = resp.Category
+
+	return count, nil
+}
+
+// GlobalCount returns the number of questions of the Open Trivia API.
+func (c *CategoryService) GlobalCount() (GlobalCount, error) {
+	req, err := c.client.NewRequest(defaultGlobalCountRoute, nil)
+	if err != nil {
+		return GlobalCount{}, err
+	}
+
+	var resp globalCountResponse
+	if _, err := c.client.Do(req, &resp); err != nil {
+		return GlobalCount{}, err
+	}
+
+	return resp.Overall, nil
+}
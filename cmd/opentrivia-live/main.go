@@ -0,0 +1,41 @@
+// Command opentrivia-live hosts live multiplayer quizzes with questions from
+// the Open Trivia API.
+//
+// Usage:
+//
+//	opentrivia-live [-addr :8080] [-upstream https://opentdb.com/]
+//
+// The rooms are created and played over HTTP, as described in the
+// documentation of the live package.
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/pinheirolucas/opentrivia"
+	"github.com/pinheirolucas/opentrivia/live"
+)
+
+func main() {
+	var (
+		addr     = flag.String("addr", ":8080", "address to listen on")
+		upstream = flag.String("upstream", "https://opentdb.com/", "base URL of the Open Trivia API")
+	)
+	flag.Parse()
+
+	raw := *upstream
+	if !strings.HasSuffix(raw, "/") {
+		raw += "/"
+	}
+
+	client, err := opentrivia.NewClient(opentrivia.WithBaseURL(raw))
+	if err != nil {
+		log.Fatalf("opentrivia-live: invalid upstream URL: %s", err)
+	}
+
+	log.Printf("opentrivia-live: listening on %s, questions from %s", *addr, client.BaseURL)
+	log.Fatal(http.ListenAndServe(*addr, live.NewServer(client)))
+}
@@ -0,0 +1,48 @@
+// Command opentrivia-proxy fronts the Open Trivia API with a caching proxy,
+// so many services can share its rate limit.
+//
+// Usage:
+//
+//	opentrivia-proxy [-addr :8080] [-upstream https://opentdb.com/] [-interval 5s]
+//
+// The services point their clients at the proxy via BaseURL. The metrics of
+// the upstream requests and of the cache are served at /debug/vars.
+package main
+
+import (
+	"expvar"
+	"flag"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/pinheirolucas/opentrivia"
+	"github.com/pinheirolucas/opentrivia/server"
+)
+
+func main() {
+	var (
+		addr     = flag.String("addr", ":8080", "address to listen on")
+		upstream = flag.String("upstream", "https://opentdb.com/", "base URL of the upstream Open Trivia API")
+		interval = flag.Duration("interval", server.DefaultUpstreamInterval, "minimum interval between upstream requests")
+	)
+	flag.Parse()
+
+	raw := *upstream
+	if !strings.HasSuffix(raw, "/") {
+		raw += "/"
+	}
+
+	client, err := opentrivia.NewClient(opentrivia.WithBaseURL(raw))
+	if err != nil {
+		log.Fatalf("opentrivia-proxy: invalid upstream URL: %s", err)
+	}
+	client.Metrics = opentrivia.NewExpvarMetrics("opentrivia")
+
+	mux := http.NewServeMux()
+	mux.Handle("/", server.NewProxy(client, *interval))
+	mux.Handle("/debug/vars", expvar.Handler())
+
+	log.Printf("opentrivia-proxy: listening on %s, proxying %s", *addr, client.BaseURL)
+	log.Fatal(http.ListenAndServe(*addr, mux))
+}
@@ -0,0 +1,59 @@
+// Command opentrivia-server serves curated questions with the Open Trivia
+// API wire format, so the opentrivia.Client works unchanged against it.
+//
+// Usage:
+//
+//	opentrivia-server -questions questions.json [-format json] [-addr :8080]
+//
+// The questions file may be written in any format of the quizformat
+// package: csv, gift, kahoot or json.
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/pinheirolucas/opentrivia/quizformat"
+	"github.com/pinheirolucas/opentrivia/server"
+)
+
+func main() {
+	var (
+		addr      = flag.String("addr", ":8080", "address to listen on")
+		path      = flag.String("questions", "questions.json", "file with the questions to serve")
+		format    = flag.String("format", "json", "format of the questions file: "+strings.Join(quizformat.Names(), ", "))
+		rateLimit = flag.Int("rate-limit", 0, "requests allowed per client IP every 5 seconds, no limit if zero")
+	)
+	flag.Parse()
+
+	qf, err := quizformat.Lookup(*format)
+	if err != nil {
+		log.Fatalf("opentrivia-server: %s", err)
+	}
+
+	f, err := os.Open(*path)
+	if err != nil {
+		log.Fatalf("opentrivia-server: %s", err)
+	}
+
+	questions, err := qf.Decode(f)
+	f.Close()
+	if err != nil {
+		log.Fatalf("opentrivia-server: error decoding %s: %s", *path, err)
+	}
+
+	store, err := server.NewMemoryStore(questions)
+	if err != nil {
+		log.Fatalf("opentrivia-server: error loading %s: %s", *path, err)
+	}
+
+	s := server.NewServer(store)
+	s.RateLimit = *rateLimit
+	s.RatePeriod = server.DefaultUpstreamInterval
+
+	log.Printf("opentrivia-server: serving %d questions on %s", len(questions), *addr)
+	log.Fatal(http.ListenAndServe(*addr, s))
+}
@@ -0,0 +1,216 @@
+package main
+
+import (
+	"flag"
+	"net/url"
+	"strconv"
+
+	"github.com/pinheirolucas/opentrivia"
+	"github.com/pkg/errors"
+)
+
+// filterFlags are the flags mirroring the question options.
+type filterFlags struct {
+	amount     uint
+	category   string
+	difficulty string
+	kind       string
+	token      string
+
+	autoRefresh          bool
+	allowUnknownCategory bool
+}
+
+func (f *filterFlags) register(fs *flag.FlagSet, amount bool) {
+	if amount {
+		fs.UintVar(&f.amount, "amount", 10, "number of questions, up to 50")
+	}
+	fs.StringVar(&f.category, "category", "", "category ID, name or alias")
+	fs.StringVar(&f.difficulty, "difficulty", "", "difficulty: easy, medium or hard")
+	fs.StringVar(&f.kind, "type", "", "question type: multiple or boolean")
+	fs.StringVar(&f.token, "token", "", "session token")
+	fs.BoolVar(&f.autoRefresh, "auto-refresh", false, "refresh the token when it is empty")
+	fs.BoolVar(&f.allowUnknownCategory, "allow-unknown-category", false, "send categories unknown to the client")
+}
+
+func (f *filterFlags) values() url.Values {
+	v := make(url.Values)
+	if f.amount > 0 {
+		v.Set("amount", strconv.FormatUint(uint64(f.amount), 10))
+	}
+	if f.category != "" {
+		v.Set("category", f.category)
+	}
+	if f.difficulty != "" {
+		v.Set("difficulty", f.difficulty)
+	}
+	if f.kind != "" {
+		v.Set("type", f.kind)
+	}
+	if f.token != "" {
+		v.Set("token", f.token)
+	}
+
+	return v
+}
+
+func questionsCommand(e *env, args []string) error {
+	var f filterFlags
+
+	fs := e.flagSet("questions")
+	f.register(fs, true)
+	if err := e.parse(fs, args); err != nil {
+		return err
+	}
+
+	options, err := opentrivia.ParseQuestionListOptions(f.values())
+	if err != nil {
+		return err
+	}
+	options.AutoRefresh = f.autoRefresh
+	options.AllowUnknownCategory = f.allowUnknownCategory
+
+	c, err := e.client()
+	if err != nil {
+		return err
+	}
+
+	questions, err := c.Question.List(options)
+	if err != nil {
+		return err
+	}
+
+	return e.write(questionRecords(questions))
+}
+
+func randomCommand(e *env, args []string) error {
+	var f filterFlags
+
+	fs := e.flagSet("random")
+	f.register(fs, false)
+	if err := e.parse(fs, args); err != nil {
+		return err
+	}
+
+	options, err := opentrivia.ParseQuestionRandomOptions(f.values())
+	if err != nil {
+		return err
+	}
+	options.AutoRefresh = f.autoRefresh
+	options.AllowUnknownCategory = f.allowUnknownCategory
+
+	c, err := e.client()
+	if err != nil {
+		return err
+	}
+
+	question, err := c.Question.Random(options)
+	if err != nil {
+		return err
+	}
+
+	return e.write(questionRecords([]opentrivia.Question{question}))
+}
+
+func categoriesCommand(e *env, args []string) error {
+	fs := e.flagSet("categories")
+	if err := e.parse(fs, args); err != nil {
+		return err
+	}
+
+	c, err := e.client()
+	if err != nil {
+		return err
+	}
+
+	categories, err := c.Category.List()
+	if err != nil {
+		return err
+	}
+
+	return e.write(categoryRecords(categories))
+}
+
+func countCommand(e *env, args []string) error {
+	var category string
+
+	fs := e.flagSet("count")
+	fs.StringVar(&category, "category", "", "category ID, name or alias; all the questions if empty")
+	if err := e.parse(fs, args); err != nil {
+		return err
+	}
+
+	c, err := e.client()
+	if err != nil {
+		return err
+	}
+
+	if category == "" {
+		count, err := c.Category.GlobalCount()
+		if err != nil {
+			return err
+		}
+
+		return e.write(globalCountRecords(count))
+	}
+
+	id, err := opentrivia.ParseQuestionCategory(category)
+	if err != nil {
+		return err
+	}
+
+	count, err := c.Category.Count(id)
+	if err != nil {
+		return err
+	}
+
+	return e.write(countRecords(count))
+}
+
+func tokenCommand(e *env, args []string) error {
+	if len(args) == 0 {
+		return errors.New("opentrivia: token requires a subcommand: create or reset")
+	}
+
+	switch args[0] {
+	case "create":
+		fs := e.flagSet("token create")
+		if err := e.parse(fs, args[1:]); err != nil {
+			return err
+		}
+
+		c, err := e.client()
+		if err != nil {
+			return err
+		}
+
+		token, err := c.Token.Create()
+		if err != nil {
+			return err
+		}
+
+		return e.write(tokenRecords(token))
+	case "reset":
+		fs := e.flagSet("token reset")
+		if err := e.parse(fs, args[1:]); err != nil {
+			return err
+		}
+		if fs.NArg() != 1 {
+			return errors.New("opentrivia: token reset requires a token")
+		}
+
+		c, err := e.client()
+		if err != nil {
+			return err
+		}
+
+		token, err := c.Token.Refresh(opentrivia.Token(fs.Arg(0)))
+		if err != nil {
+			return err
+		}
+
+		return e.write(tokenRecords(token))
+	}
+
+	return errors.Errorf("opentrivia: unknown token subcommand %q", args[0])
+}
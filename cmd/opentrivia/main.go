@@ -0,0 +1,138 @@
+// Command opentrivia fetches and exports questions from the Open Trivia API.
+//
+// Usage:
+//
+//	opentrivia <command> [flags]
+//
+// The commands are:
+//
+//	questions    list questions
+//	random       show a random question
+//	categories   list the categories
+//	count        show the number of questions of a category
+//	token        create or reset a session token
+//	play         play a quiz in the terminal
+//
+// Every command accepts the --base-url flag, to point at a mirror or a local
+// mock, and the --format flag, to choose between table, json, jsonl and csv.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pinheirolucas/opentrivia"
+)
+
+const usage = `Usage: opentrivia <command> [flags]
+
+Commands:
+  questions     list questions
+  random        show a random question
+  categories    list the categories
+  count         show the number of questions of a category
+  token create  create a session token
+  token reset   reset a session token
+  play          play a quiz in the terminal
+
+Run "opentrivia <command> -h" to see the flags of a command.
+`
+
+type command func(env *env, args []string) error
+
+var commands = map[string]command{
+	"questions":  questionsCommand,
+	"random":     randomCommand,
+	"categories": categoriesCommand,
+	"count":      countCommand,
+	"token":      tokenCommand,
+	"play":       playCommand,
+}
+
+// env holds the state shared by the commands.
+type env struct {
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+	now    func() time.Time
+
+	baseURL string
+	format  string
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return 2
+	}
+
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(stderr, "opentrivia: unknown command %q\n\n%s", args[0], usage)
+		return 2
+	}
+
+	e := &env{
+		stdin:  stdin,
+		stdout: stdout,
+		stderr: stderr,
+		now:    time.Now,
+	}
+
+	if err := cmd(e, args[1:]); err != nil {
+		if err == flag.ErrHelp {
+			return 2
+		}
+
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+
+	return 0
+}
+
+// flagSet returns a flag set with the flags shared by all the commands.
+func (e *env) flagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(e.stderr)
+	fs.StringVar(&e.baseURL, "base-url", "", "base URL of the Open Trivia API")
+	fs.StringVar(&e.format, "format", formatTable, "output format: table, json, jsonl or csv")
+
+	return fs
+}
+
+// parse parses the arguments with the flag set, and checks the output
+// format before any request is made.
+func (e *env) parse(fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	return checkFormat(e.format)
+}
+
+// client returns a client pointed at the provided base URL.
+func (e *env) client() (*opentrivia.Client, error) {
+	if e.baseURL == "" {
+		return opentrivia.NewClient()
+	}
+
+	raw := e.baseURL
+	if !strings.HasSuffix(raw, "/") {
+		raw += "/"
+	}
+
+	return opentrivia.NewClient(opentrivia.WithBaseURL(raw))
+}
+
+func (e *env) write(r *records) error {
+	return r.write(e.stdout, e.format)
+}
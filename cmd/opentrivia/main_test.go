@@ -0,0 +1,128 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/pinheirolucas/opentrivia"
+	"github.com/pinheirolucas/opentrivia/opentriviatest"
+)
+
+func newServer(t *testing.T) *opentriviatest.Server {
+	server := opentriviatest.NewServer()
+	t.Cleanup(server.Close)
+
+	server.SetQuestions([]opentrivia.Question{
+		{
+			Category:         opentrivia.QuestionCategoryComputer.Name(),
+			Type:             string(opentrivia.QuestionTypeMultiple),
+			Difficulty:       string(opentrivia.QuestionDifficultyHard),
+			Question:         "What does CPU stand for?",
+			CorrectAnswer:    "Central Processing Unit",
+			IncorrectAnswers: []string{"Computer Personal Unit", "Central Process Unit", "Central Processor Unit"},
+		},
+	})
+
+	return server
+}
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+
+	server := newServer(t)
+
+	t.Run("expect the questions to be written as csv", func(t *testing.T) {
+		t.Parallel()
+
+		var stdout, stderr bytes.Buffer
+		args := []string{"questions", "--base-url", server.URL, "--format", "csv", "--amount", "1", "--difficulty", "hard", "--category", "computers"}
+
+		if code := run(args, nil, &stdout, &stderr); code != 0 {
+			t.Fatalf("Expected exit code 0, got %d: %s", code, stderr.String())
+		}
+
+		lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+		if len(lines) != 2 {
+			t.Fatalf("Expected a header and a row, got %q", stdout.String())
+		}
+		if !strings.Contains(lines[1], ",hard,") {
+			t.Errorf("Expected the difficulty to be sent, got %q", lines[1])
+		}
+	})
+
+	t.Run("expect the categories to be written as jsonl", func(t *testing.T) {
+		t.Parallel()
+
+		var stdout, stderr bytes.Buffer
+		args := []string{"categories", "--base-url", server.URL, "--format", "jsonl"}
+
+		if code := run(args, nil, &stdout, &stderr); code != 0 {
+			t.Fatalf("Expected exit code 0, got %d: %s", code, stderr.String())
+		}
+
+		const expected = `{"id":18,"name":"Science: Computers"}` + "\n"
+		if stdout.String() != expected {
+			t.Errorf("Expected %q, got %q", expected, stdout.String())
+		}
+	})
+
+	t.Run("expect the token to be written as a table", func(t *testing.T) {
+		t.Parallel()
+
+		var stdout, stderr bytes.Buffer
+		args := []string{"token", "create", "--base-url", server.URL}
+
+		if code := run(args, nil, &stdout, &stderr); code != 0 {
+			t.Fatalf("Expected exit code 0, got %d: %s", code, stderr.String())
+		}
+
+		lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+		if len(lines) != 2 || lines[0] != "TOKEN" || lines[1] == "" {
+			t.Errorf("Expected the token to be written, got %q", stdout.String())
+		}
+	})
+
+	t.Run("expect invalid options to fail before the request", func(t *testing.T) {
+		t.Parallel()
+
+		var stdout, stderr bytes.Buffer
+		args := []string{"random", "--base-url", "http://127.0.0.1:0", "--difficulty", "hrad"}
+
+		if code := run(args, nil, &stdout, &stderr); code != 1 {
+			t.Fatalf("Expected exit code 1, got %d", code)
+		}
+		if !strings.Contains(stderr.String(), "Difficulty") {
+			t.Errorf("Expected a difficulty error, got %q", stderr.String())
+		}
+	})
+
+	t.Run("expect an unknown format to fail before the request", func(t *testing.T) {
+		t.Parallel()
+
+		server := newServer(t)
+
+		var stdout, stderr bytes.Buffer
+		args := []string{"categories", "--base-url", server.URL, "--format", "xml"}
+
+		if code := run(args, nil, &stdout, &stderr); code != 1 {
+			t.Fatalf("Expected exit code 1, got %d", code)
+		}
+		if !strings.Contains(stderr.String(), "unknown format") {
+			t.Errorf("Expected a format error, got %q", stderr.String())
+		}
+		if requests := server.Requests(); requests != 0 {
+			t.Errorf("Expected no requests, got %d", requests)
+		}
+	})
+
+	t.Run("expect an unknown command to fail", func(t *testing.T) {
+		t.Parallel()
+
+		var stdout, stderr bytes.Buffer
+
+		if code := run([]string{"unknown"}, nil, &stdout, &stderr); code != 2 {
+			t.Errorf("Expected exit code 2, got %d", code)
+		}
+	})
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/pinheirolucas/opentrivia"
+	"github.com/pkg/errors"
+)
+
+const (
+	formatTable = "table"
+	formatJSON  = "json"
+	formatJSONL = "jsonl"
+	formatCSV   = "csv"
+)
+
+// records is the output of a command. The header and rows are used by the
+// table and csv formats, while the values are used by the json formats.
+type records struct {
+	header []string
+	rows   [][]string
+	values []interface{}
+}
+
+func (r *records) add(value interface{}, row ...string) {
+	r.values = append(r.values, value)
+	r.rows = append(r.rows, row)
+}
+
+func (r *records) write(w io.Writer, format string) error {
+	switch format {
+	case formatTable:
+		tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+		fmt.Fprintln(tw, strings.ToUpper(strings.Join(r.header, "\t")))
+		for _, row := range r.rows {
+			fmt.Fprintln(tw, strings.Join(row, "\t"))
+		}
+
+		return tw.Flush()
+	case formatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+
+		values := r.values
+		if values == nil {
+			values = []interface{}{}
+		}
+
+		return enc.Encode(values)
+	case formatJSONL:
+		enc := json.NewEncoder(w)
+		for _, v := range r.values {
+			if err := enc.Encode(v); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	case formatCSV:
+		cw := csv.NewWriter(w)
+		if err := cw.Write(r.header); err != nil {
+			return err
+		}
+		if err := cw.WriteAll(r.rows); err != nil {
+			return err
+		}
+
+		return cw.Error()
+	}
+
+	return checkFormat(format)
+}
+
+// checkFormat returns an error if the output format is unknown.
+func checkFormat(format string) error {
+	switch format {
+	case formatTable, formatJSON, formatJSONL, formatCSV:
+		return nil
+	}
+
+	return errors.Errorf("opentrivia: unknown format %q", format)
+}
+
+func questionRecords(questions []opentrivia.Question) *records {
+	r := &records{
+		header: []string{"category", "type", "difficulty", "question", "correct_answer", "incorrect_answers"},
+	}
+
+	for _, q := range questions {
+		r.add(q,
+			q.Category,
+			q.Type,
+			q.Difficulty,
+			q.Question,
+			q.CorrectAnswer,
+			strings.Join(q.IncorrectAnswers, "|"),
+		)
+	}
+
+	return r
+}
+
+func categoryRecords(categories []opentrivia.Category) *records {
+	r := &records{
+		header: []string{"id", "name"},
+	}
+
+	for _, c := range categories {
+		r.add(c, strconv.Itoa(int(c.ID)), c.Name)
+	}
+
+	return r
+}
+
+func countRecords(count opentrivia.CategoryCount) *records {
+	r := &records{
+		header: []string{"category", "total", "easy", "medium", "hard"},
+	}
+
+	r.add(count,
+		strconv.Itoa(int(count.Category)),
+		strconv.Itoa(count.Total),
+		strconv.Itoa(count.Easy),
+		strconv.Itoa(count.Medium),
+		strconv.Itoa(count.Hard),
+	)
+
+	return r
+}
+
+func globalCountRecords(count opentrivia.GlobalCount) *records {
+	r := &records{
+		header: []string{"total", "pending", "verified", "rejected"},
+	}
+
+	r.add(count,
+		strconv.Itoa(count.Total),
+		strconv.Itoa(count.Pending),
+		strconv.Itoa(count.Verified),
+		strconv.Itoa(count.Rejected),
+	)
+
+	return r
+}
+
+func tokenRecords(token opentrivia.Token) *records {
+	r := &records{
+		header: []string{"token"},
+	}
+
+	r.add(map[string]opentrivia.Token{"token": token}, string(token))
+
+	return r
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"time"
+
+	"github.com/pinheirolucas/opentrivia"
+)
+
+// difficultyWeights are the points given to a correct answer of each
+// difficulty.
+var difficultyWeights = map[string]int{
+	string(opentrivia.QuestionDifficultyEasy):   1,
+	string(opentrivia.QuestionDifficultyMedium): 2,
+	string(opentrivia.QuestionDifficultyHard):   3,
+}
+
+// playResult is the outcome of a single question of a quiz.
+type playResult struct {
+	question opentrivia.Question
+	answer   string
+	correct  bool
+	timedOut bool
+	elapsed  time.Duration
+	points   int
+}
+
+func playCommand(e *env, args []string) error {
+	var (
+		f         filterFlags
+		timeLimit time.Duration
+	)
+
+	fs := e.flagSet("play")
+	f.register(fs, true)
+	fs.DurationVar(&timeLimit, "time-limit", 0, "time to answer each question, no limit if zero")
+	if err := e.parse(fs, args); err != nil {
+		return err
+	}
+
+	options, err := opentrivia.ParseQuestionListOptions(f.values())
+	if err != nil {
+		return err
+	}
+	options.AutoRefresh = f.autoRefresh
+	options.AllowUnknownCategory = f.allowUnknownCategory
+
+	c, err := e.client()
+	if err != nil {
+		return err
+	}
+
+	questions, err := c.Question.List(options)
+	if err != nil {
+		return err
+	}
+
+	in := bufio.NewScanner(e.stdin)
+	results := make([]playResult, 0, len(questions))
+	for i, q := range questions {
+		fmt.Fprintf(e.stdout, "\nQuestion %d of %d (%s, %s)\n", i+1, len(questions), html.UnescapeString(q.Category), q.Difficulty)
+
+		r, ok := e.ask(in, q, timeLimit)
+		if !ok {
+			fmt.Fprintln(e.stdout, "\nNo more answers, ending the quiz.")
+			break
+		}
+		results = append(results, r)
+
+		switch {
+		case r.timedOut:
+			fmt.Fprintf(e.stdout, "Time is up! The answer was %s.\n", html.UnescapeString(q.CorrectAnswer))
+		case r.correct:
+			fmt.Fprintf(e.stdout, "Correct! +%d\n", r.points)
+		default:
+			fmt.Fprintf(e.stdout, "Wrong! The answer was %s.\n", html.UnescapeString(q.CorrectAnswer))
+		}
+	}
+
+	e.summary(results)
+
+	return nil
+}
+
+// ask shows the question and reads the answer. It returns false when there
+// are no more answers to read.
+func (e *env) ask(in *bufio.Scanner, q opentrivia.Question, timeLimit time.Duration) (playResult, bool) {
+	r := playResult{question: q}
+
+	fmt.Fprintln(e.stdout, html.UnescapeString(q.Question))
+	answers := q.ShuffleAnswers()
+	for i, a := range answers {
+		fmt.Fprintf(e.stdout, "  %c) %s\n", 'A'+i, html.UnescapeString(a))
+	}
+	fmt.Fprint(e.stdout, "> ")
+
+	start := e.now()
+	if !in.Scan() {
+		return r, false
+	}
+	r.elapsed = e.now().Sub(start)
+
+	r.answer = opentrivia.ChooseAnswer(answers, in.Text())
+	if timeLimit > 0 && r.elapsed > timeLimit {
+		r.timedOut = true
+		return r, true
+	}
+
+	r.correct = q.IsAnswerCorrect(r.answer)
+	if r.correct {
+		r.points = difficultyWeights[q.Difficulty]
+	}
+
+	return r, true
+}
+
+func (e *env) summary(results []playResult) {
+	var (
+		correct, points, maxPoints int
+		elapsed                    time.Duration
+	)
+
+	for _, r := range results {
+		if r.correct {
+			correct++
+		}
+
+		points += r.points
+		maxPoints += difficultyWeights[r.question.Difficulty]
+		elapsed += r.elapsed
+	}
+
+	fmt.Fprintln(e.stdout, "\nSummary")
+	fmt.Fprintf(e.stdout, "  Correct answers: %d of %d\n", correct, len(results))
+	fmt.Fprintf(e.stdout, "  Score:           %d of %d\n", points, maxPoints)
+	fmt.Fprintf(e.stdout, "  Total time:      %s\n", elapsed.Round(time.Millisecond))
+	if len(results) > 0 {
+		fmt.Fprintf(e.stdout, "  Average time:    %s\n", (elapsed / time.Duration(len(results))).Round(time.Millisecond))
+	}
+}
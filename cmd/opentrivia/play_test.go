@@ -0,0 +1,92 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newClock returns a clock that advances by step on every call.
+func newClock(step time.Duration) func() time.Time {
+	now := time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC)
+	return func() time.Time {
+		now = now.Add(step)
+		return now
+	}
+}
+
+func TestPlayCommand(t *testing.T) {
+	t.Parallel()
+
+	server := newServer(t)
+
+	t.Run("expect a correct answer to be weighted by difficulty", func(t *testing.T) {
+		t.Parallel()
+
+		var stdout bytes.Buffer
+		e := &env{
+			stdin:  strings.NewReader("central processing unit\n"),
+			stdout: &stdout,
+			stderr: &stdout,
+			now:    newClock(2 * time.Second),
+		}
+
+		args := []string{"--base-url", server.URL, "--amount", "1", "--difficulty", "hard"}
+		if err := playCommand(e, args); err != nil {
+			t.Fatal(err)
+		}
+
+		output := stdout.String()
+		if !strings.Contains(output, "Correct! +3") {
+			t.Errorf("Expected a hard question to be worth 3 points, got %q", output)
+		}
+		if !strings.Contains(output, "Score:           3 of 3") {
+			t.Errorf("Expected the score on the summary, got %q", output)
+		}
+		if !strings.Contains(output, "Total time:      2s") {
+			t.Errorf("Expected the time on the summary, got %q", output)
+		}
+	})
+
+	t.Run("expect a slow answer to time out", func(t *testing.T) {
+		t.Parallel()
+
+		var stdout bytes.Buffer
+		e := &env{
+			stdin:  strings.NewReader("A\n"),
+			stdout: &stdout,
+			stderr: &stdout,
+			now:    newClock(time.Minute),
+		}
+
+		args := []string{"--base-url", server.URL, "--amount", "1", "--time-limit", "30s"}
+		if err := playCommand(e, args); err != nil {
+			t.Fatal(err)
+		}
+
+		if !strings.Contains(stdout.String(), "Time is up!") {
+			t.Errorf("Expected the question to time out, got %q", stdout.String())
+		}
+	})
+
+	t.Run("expect the quiz to end without input", func(t *testing.T) {
+		t.Parallel()
+
+		var stdout bytes.Buffer
+		e := &env{
+			stdin:  strings.NewReader(""),
+			stdout: &stdout,
+			stderr: &stdout,
+			now:    newClock(time.Second),
+		}
+
+		if err := playCommand(e, []string{"--base-url", server.URL, "--amount", "1"}); err != nil {
+			t.Fatal(err)
+		}
+
+		if !strings.Contains(stdout.String(), "Correct answers: 0 of 0") {
+			t.Errorf("Expected an empty summary, got %q", stdout.String())
+		}
+	})
+}
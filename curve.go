@@ -0,0 +1,252 @@
+package opentrivia
+
+import "github.com/pkg/errors"
+
+// DifficultyCurve describes how the difficulty of a quiz evolves from the
+// first to the last question.
+type DifficultyCurve interface {
+	// Difficulty returns the difficulty of the question at the provided
+	// zero-based position of a quiz with the provided length.
+	Difficulty(position, length int) QuestionDifficulty
+}
+
+// DifficultyCurveFunc is an adapter to allow the use of ordinary functions
+// as a DifficultyCurve.
+type DifficultyCurveFunc func(position, length int) QuestionDifficulty
+
+// Difficulty calls f(position, length).
+func (f DifficultyCurveFunc) Difficulty(position, length int) QuestionDifficulty {
+	return f(position, length)
+}
+
+var questionDifficulties = []QuestionDifficulty{
+	QuestionDifficultyEasy,
+	QuestionDifficultyMedium,
+	QuestionDifficultyHard,
+}
+
+// LinearCurve splits the quiz in three equal parts: easy, medium and hard.
+var LinearCurve DifficultyCurve = DifficultyCurveFunc(func(position, length int) QuestionDifficulty {
+	if length <= 0 {
+		return QuestionDifficultyEasy
+	}
+
+	i := position * len(questionDifficulties) / length
+	if i >= len(questionDifficulties) {
+		i = len(questionDifficulties) - 1
+	}
+
+	return questionDifficulties[i]
+})
+
+// StepCurve returns a curve that starts easy, switches to medium at the
+// mediumFrom position and to hard at the hardFrom position.
+func StepCurve(mediumFrom, hardFrom int) DifficultyCurve {
+	return DifficultyCurveFunc(func(position, length int) QuestionDifficulty {
+		switch {
+		case position >= hardFrom:
+			return QuestionDifficultyHard
+		case position >= mediumFrom:
+			return QuestionDifficultyMedium
+		}
+
+		return QuestionDifficultyEasy
+	})
+}
+
+// CustomCurve returns a curve that uses the provided difficulty for each
+// position. Positions beyond the provided difficulties repeat the last one.
+func CustomCurve(difficulties ...QuestionDifficulty) DifficultyCurve {
+	return DifficultyCurveFunc(func(position, length int) QuestionDifficulty {
+		if len(difficulties) == 0 {
+			return QuestionDifficultyEasy
+		}
+		if position >= len(difficulties) {
+			return difficulties[len(difficulties)-1]
+		}
+
+		return difficulties[position]
+	})
+}
+
+// difficultyFallbacks returns the difficulties to try, in order, when
+// there are no questions left for the provided difficulty.
+func difficultyFallbacks(d QuestionDifficulty) []QuestionDifficulty {
+	switch d {
+	case QuestionDifficultyEasy:
+		return []QuestionDifficulty{QuestionDifficultyEasy, QuestionDifficultyMedium, QuestionDifficultyHard}
+	case QuestionDifficultyMedium:
+		return []QuestionDifficulty{QuestionDifficultyMedium, QuestionDifficultyEasy, QuestionDifficultyHard}
+	case QuestionDifficultyHard:
+		return []QuestionDifficulty{QuestionDifficultyHard, QuestionDifficultyMedium, QuestionDifficultyEasy}
+	}
+
+	return []QuestionDifficulty{d}
+}
+
+// DefaultQuestionLadderOptions is the default options of Question Ladder
+// method.
+var DefaultQuestionLadderOptions = &QuestionLadderOptions{
+	AutoRefresh: false,
+	Curve:       LinearCurve,
+	Length:      15,
+}
+
+// QuestionLadderOptions are the options for QuestionService Ladder
+// method.
+type QuestionLadderOptions struct {
+	// If true, the request will refresh the provided token when needed.
+	AutoRefresh bool
+
+	// If true, categories unknown to this client will be sent to the Open
+	// Trivia API.
+	AllowUnknownCategory bool
+
+	// Defaults to opentrivia.LinearCurve.
+	Curve DifficultyCurve
+
+	// The maximum length is 50.
+	Length uint8
+
+	Category QuestionCategory
+
+	// A token is recommended to avoid repeated questions when a
+	// difficulty falls back to an adjacent one.
+	Token Token
+
+	Type QuestionType
+}
+
+// LadderQuestion is a question placed on a position of a ladder.
+type LadderQuestion struct {
+	// Position is the zero-based position of the question on the ladder.
+	Position int
+
+	// Difficulty is the difficulty requested by the curve for the
+	// position. It may differ from Question.Difficulty when there were no
+	// questions left for the requested difficulty.
+	Difficulty QuestionDifficulty
+
+	Question Question
+}
+
+// Ladder returns questions ordered by the provided difficulty curve, from
+// the first to the last position of the quiz.
+//
+// When the Open Trivia API has not enough questions of the difficulty of
+// some positions, Ladder keeps the questions it has and fills the remaining
+// positions with the adjacent difficulties. If they can not be filled, the
+// request will return an opentrivia.ErrNoResults.
+//
+// If options is nil, Ladder will use opentrivia.DefaultQuestionLadderOptions.
+func (q *QuestionService) Ladder(options *QuestionLadderOptions) ([]LadderQuestion, error) {
+	if options == nil {
+		options = DefaultQuestionLadderOptions
+	}
+
+	curve := options.Curve
+	if curve == nil {
+		curve = LinearCurve
+	}
+
+	length := int(options.Length)
+	if length <= 0 {
+		length = int(DefaultQuestionLadderOptions.Length)
+	} else if length > 50 {
+		length = 50
+	}
+
+	ladder := make([]LadderQuestion, length)
+	var difficulties []QuestionDifficulty
+	positions := make(map[QuestionDifficulty][]int)
+	for i := range ladder {
+		d := curve.Difficulty(i, length)
+		if _, ok := positions[d]; !ok {
+			difficulties = append(difficulties, d)
+		}
+
+		ladder[i] = LadderQuestion{Position: i, Difficulty: d}
+		positions[d] = append(positions[d], i)
+	}
+
+	// Questions are requested once per difficulty to spare requests.
+	token := options.Token
+	seen := make(map[string]bool)
+	for _, d := range difficulties {
+		p := positions[d]
+		questions, err := q.ladderQuestions(options, &token, seen, d, len(p))
+		if err != nil {
+			return []LadderQuestion{}, err
+		}
+
+		for i, position := range p {
+			ladder[position].Question = questions[i]
+		}
+	}
+
+	return ladder, nil
+}
+
+// ladderQuestions returns n questions of the difficulty, completed with the
+// questions of the adjacent difficulties when there are not enough of them.
+// The questions in seen are skipped, and the returned ones are added to it.
+func (q *QuestionService) ladderQuestions(options *QuestionLadderOptions, token *Token, seen map[string]bool, d QuestionDifficulty, n int) ([]Question, error) {
+	questions := make([]Question, 0, n)
+
+	for _, fallback := range difficultyFallbacks(d) {
+		// The Open Trivia API has no results when it has less questions
+		// than requested, or less left for the token, so the amount is
+		// halved until it has some.
+		amount := n - len(questions)
+		for amount > 0 {
+			listOptions := &QuestionListOptions{
+				AutoRefresh:          options.AutoRefresh,
+				AllowUnknownCategory: options.AllowUnknownCategory,
+				Category:             options.Category,
+				Difficulty:           fallback,
+				Limit:                uint8(amount),
+				Token:                *token,
+				Type:                 options.Type,
+			}
+
+			list, err := q.client.Question.List(listOptions)
+			if cause := errors.Cause(err); cause == ErrNoResults || cause == ErrTokenEmpty {
+				amount /= 2
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+
+			// List may have refreshed the token.
+			*token = listOptions.Token
+
+			added := 0
+			for _, question := range list {
+				fingerprint := question.Fingerprint()
+				if seen[fingerprint] || len(questions) == n {
+					continue
+				}
+
+				seen[fingerprint] = true
+				questions = append(questions, question)
+				added++
+			}
+
+			// Without a token, the same questions may be returned again.
+			if added == 0 {
+				break
+			}
+
+			if remaining := n - len(questions); amount > remaining {
+				amount = remaining
+			}
+		}
+
+		if len(questions) == n {
+			return questions, nil
+		}
+	}
+
+	return nil, ErrNoResults
+}
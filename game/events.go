@@ -0,0 +1,101 @@
+package game
+
+import "time"
+
+// EventType identifies what happened in a game.
+type EventType string
+
+// Event types of a game.
+const (
+	EventRoundStarted    EventType = "round_started"
+	EventQuestionStarted EventType = "question_started"
+	EventAnswerReceived  EventType = "answer_received"
+	EventPaused          EventType = "paused"
+	EventResumed         EventType = "resumed"
+	EventQuestionEnded   EventType = "question_ended"
+	EventRoundEnded      EventType = "round_ended"
+	EventGameEnded       EventType = "game_ended"
+)
+
+// Event is something that happened in a game. Only the fields relevant to
+// its type are set.
+type Event struct {
+	Type EventType `json:"type"`
+	Time time.Time `json:"time"`
+
+	// Round is the one-based number of the current round.
+	Round int `json:"round"`
+
+	// Question is set for question events, pauses and answers.
+	Question *Question `json:"question,omitempty"`
+
+	// Answer is set for EventAnswerReceived.
+	Answer *Answer `json:"answer,omitempty"`
+
+	// Answers are the answers received for the question, in the order
+	// they arrived. Set for EventQuestionEnded.
+	Answers []Answer `json:"answers,omitempty"`
+
+	// Leaderboard is set for EventQuestionEnded, EventRoundEnded and
+	// EventGameEnded.
+	Leaderboard []Player `json:"leaderboard,omitempty"`
+}
+
+// emit queues the event to be delivered on the events channel. It must be
+// called with g.mu held.
+func (g *Game) emit(e Event) {
+	if g.events == nil || g.closed {
+		return
+	}
+
+	e.Time = g.clock.Now()
+	e.Round = g.round
+
+	g.pending = append(g.pending, e)
+	g.cond.Signal()
+}
+
+// Events returns the channel on which the events of the game are delivered,
+// in order. The events are queued, so a slow reader never blocks the game,
+// and only the events emitted after the first call are delivered.
+//
+// The channel is closed after Close, once the queued events are delivered,
+// so it must be drained until then.
+func (g *Game) Events() <-chan Event {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.events == nil {
+		g.events = make(chan Event)
+		if g.closed {
+			close(g.events)
+		} else {
+			go g.dispatch()
+		}
+	}
+
+	return g.events
+}
+
+// dispatch delivers the queued events until the game is closed and the
+// queue is empty.
+func (g *Game) dispatch() {
+	defer close(g.events)
+
+	for {
+		g.mu.Lock()
+		for len(g.pending) == 0 && !g.closed {
+			g.cond.Wait()
+		}
+		if len(g.pending) == 0 {
+			g.mu.Unlock()
+			return
+		}
+
+		e := g.pending[0]
+		g.pending = g.pending[1:]
+		g.mu.Unlock()
+
+		g.events <- e
+	}
+}
@@ -0,0 +1,560 @@
+// Package game implements the loop of a quiz game on top of the Open Trivia
+// API: players, rounds of questions, time limits, scoring and an event
+// stream.
+//
+// A Game is passive: the caller drives it, usually from a timer or from the
+// host of the game, and the players answer concurrently:
+//
+//	g := game.New(client.Question, game.Config{TimeLimit: 20 * time.Second})
+//	defer g.Close()
+//
+//	g.AddPlayer("ana", "Ana")
+//	g.StartRound()
+//	q, err := g.NextQuestion()
+//	...
+//	g.Answer("ana", q.Answers[0])
+//	g.EndQuestion()
+package game
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pinheirolucas/opentrivia"
+	"github.com/pkg/errors"
+)
+
+// Errors returned by the methods of a Game.
+var (
+	ErrPlayerExists     = errors.New("game: player already exists")
+	ErrUnknownPlayer    = errors.New("game: unknown player")
+	ErrRoundInProgress  = errors.New("game: round in progress")
+	ErrNoRound          = errors.New("game: no round in progress")
+	ErrRoundOver        = errors.New("game: no more questions in the round")
+	ErrNoQuestion       = errors.New("game: no question in progress")
+	ErrAlreadyAnswered  = errors.New("game: question already answered")
+	ErrTooLate          = errors.New("game: time is up")
+	ErrGameOver         = errors.New("game: game over")
+	ErrPaused           = errors.New("game: game paused")
+	ErrNoQuestionSource = errors.New("game: no question source")
+)
+
+// Source provides the questions of the rounds. It is implemented by
+// *opentrivia.QuestionService.
+type Source interface {
+	List(options *opentrivia.QuestionListOptions) ([]opentrivia.Question, error)
+}
+
+// Clock tells the current time.
+type Clock interface {
+	Now() time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+// Round configures a round of a game.
+type Round struct {
+	// Options are the options used to fetch the questions of the round. If
+	// nil, opentrivia.DefaultQuestionListOptions is used.
+	Options *opentrivia.QuestionListOptions
+
+	// TimeLimit overrides the time limit of the game for this round.
+	TimeLimit time.Duration
+}
+
+// Config configures a game.
+type Config struct {
+	// Rounds of the game. If empty, the game has a single round with the
+	// default options.
+	Rounds []Round
+
+	// TimeLimit is the time to answer each question. If zero, there is no
+	// time limit and no speed bonus.
+	TimeLimit time.Duration
+
+	// Scoring of the correct answers. If nil, DefaultScoring is used.
+	Scoring *Scoring
+
+	// Clock used to time the answers. If nil, the system clock is used.
+	Clock Clock
+}
+
+// Player is a participant of a game.
+type Player struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	Score      int    `json:"score"`
+	Streak     int    `json:"streak"`
+	BestStreak int    `json:"best_streak"`
+	Correct    int    `json:"correct"`
+	Answered   int    `json:"answered"`
+}
+
+// Question is a question being played.
+type Question struct {
+	Question opentrivia.Question `json:"question"`
+
+	// Number is the one-based position of the question in its round, out
+	// of Total questions.
+	Number int `json:"number"`
+	Total  int `json:"total"`
+
+	// Answers are the shuffled answers shown to the players.
+	Answers []string `json:"answers"`
+
+	// Started is when the question started. Deadline is when the time to
+	// answer it ends, zero if there is no time limit.
+	Started  time.Time `json:"started"`
+	Deadline time.Time `json:"deadline,omitempty"`
+}
+
+// Answer is the answer of a player to a question.
+type Answer struct {
+	PlayerID string        `json:"player_id"`
+	Answer   string        `json:"answer"`
+	Correct  bool          `json:"correct"`
+	Points   int           `json:"points"`
+	Elapsed  time.Duration `json:"elapsed"`
+	Streak   int           `json:"streak"`
+}
+
+// Game is a quiz game. It is safe for concurrent use.
+type Game struct {
+	source  Source
+	config  Config
+	scoring Scoring
+	clock   Clock
+
+	mu       sync.Mutex
+	players  map[string]*Player
+	order    []string
+	round    int
+	inRound  bool
+	fetching bool
+	over     bool
+
+	questions []opentrivia.Question
+	next      int
+	current   *Question
+	timeLimit time.Duration
+	answers   []Answer
+	answered  map[string]bool
+	paused    time.Time
+
+	closed  bool
+	events  chan Event
+	pending []Event
+	cond    *sync.Cond
+}
+
+// New returns a game whose questions come from source.
+func New(source Source, config Config) *Game {
+	g := &Game{
+		source:  source,
+		config:  config,
+		scoring: DefaultScoring,
+		clock:   config.Clock,
+		players: make(map[string]*Player),
+	}
+
+	if config.Scoring != nil {
+		g.scoring = *config.Scoring
+	}
+	if g.clock == nil {
+		g.clock = systemClock{}
+	}
+	if len(g.config.Rounds) == 0 {
+		g.config.Rounds = []Round{{}}
+	}
+
+	g.cond = sync.NewCond(&g.mu)
+
+	return g
+}
+
+// AddPlayer adds a player to the game. Players can join at any time before
+// the game is over.
+func (g *Game) AddPlayer(id, name string) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.over {
+		return ErrGameOver
+	}
+	if _, ok := g.players[id]; ok {
+		return ErrPlayerExists
+	}
+
+	g.players[id] = &Player{ID: id, Name: name}
+	g.order = append(g.order, id)
+
+	return nil
+}
+
+// Player returns the player with the provided id.
+func (g *Game) Player(id string) (Player, bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	p, ok := g.players[id]
+	if !ok {
+		return Player{}, false
+	}
+
+	return *p, true
+}
+
+// Leaderboard returns the players ordered by score. Ties keep the order in
+// which the players joined.
+func (g *Game) Leaderboard() []Player {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return g.leaderboard()
+}
+
+func (g *Game) leaderboard() []Player {
+	players := make([]Player, len(g.order))
+	for i, id := range g.order {
+		players[i] = *g.players[id]
+	}
+
+	sort.SliceStable(players, func(i, j int) bool {
+		return players[i].Score > players[j].Score
+	})
+
+	return players
+}
+
+// Current returns the question in progress, if any.
+func (g *Game) Current() (Question, bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.current == nil {
+		return Question{}, false
+	}
+
+	return *g.current, true
+}
+
+// Over reports whether all the rounds were played or the game was closed.
+func (g *Game) Over() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return g.over
+}
+
+// StartRound fetches the questions of the next round from the source.
+//
+// It returns ErrRoundInProgress if the previous round did not end and
+// ErrGameOver if there are no more rounds.
+func (g *Game) StartRound() error {
+	g.mu.Lock()
+	if g.over {
+		g.mu.Unlock()
+		return ErrGameOver
+	}
+	if g.inRound || g.fetching {
+		g.mu.Unlock()
+		return ErrRoundInProgress
+	}
+	if g.source == nil {
+		g.mu.Unlock()
+		return ErrNoQuestionSource
+	}
+
+	r := g.config.Rounds[g.round]
+	g.fetching = true
+	g.mu.Unlock()
+
+	// The options are copied, so List does not change the configuration
+	// when it fills the defaults or refreshes the token.
+	var options *opentrivia.QuestionListOptions
+	if r.Options != nil {
+		o := *r.Options
+		options = &o
+	}
+
+	// The source may take a while, so the lock is not held, and the
+	// players can keep joining.
+	questions, err := g.source.List(options)
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.fetching = false
+	if err != nil {
+		return err
+	}
+	if g.over {
+		return ErrGameOver
+	}
+
+	g.round++
+	g.inRound = true
+	g.questions = questions
+	g.next = 0
+	g.timeLimit = g.config.TimeLimit
+	if r.TimeLimit > 0 {
+		g.timeLimit = r.TimeLimit
+	}
+
+	g.emit(Event{Type: EventRoundStarted})
+
+	return nil
+}
+
+// NextQuestion ends the question in progress, if any, and starts the next
+// question of the round.
+//
+// It returns ErrRoundOver when all the questions of the round were played.
+func (g *Game) NextQuestion() (Question, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.over {
+		return Question{}, ErrGameOver
+	}
+	if !g.inRound {
+		return Question{}, ErrNoRound
+	}
+
+	g.endQuestion()
+
+	if g.next >= len(g.questions) {
+		return Question{}, ErrRoundOver
+	}
+
+	q := g.questions[g.next]
+	g.next++
+
+	now := g.clock.Now()
+	g.current = &Question{
+		Question: q,
+		Number:   g.next,
+		Total:    len(g.questions),
+		Answers:  q.ShuffleAnswers(),
+		Started:  now,
+	}
+	if g.timeLimit > 0 {
+		g.current.Deadline = now.Add(g.timeLimit)
+	}
+	g.answers = nil
+	g.answered = make(map[string]bool)
+
+	current := *g.current
+	g.emit(Event{Type: EventQuestionStarted, Question: &current})
+
+	return current, nil
+}
+
+// Answer records the answer of a player to the question in progress and
+// scores it.
+//
+// It returns ErrTooLate if the time limit has passed, even if the question
+// was not ended yet.
+func (g *Game) Answer(playerID, answer string) (Answer, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	p, ok := g.players[playerID]
+	if !ok {
+		return Answer{}, ErrUnknownPlayer
+	}
+	if g.current == nil {
+		return Answer{}, ErrNoQuestion
+	}
+	if g.answered[playerID] {
+		return Answer{}, ErrAlreadyAnswered
+	}
+
+	if !g.paused.IsZero() {
+		return Answer{}, ErrPaused
+	}
+
+	now := g.clock.Now()
+	if !g.current.Deadline.IsZero() && now.After(g.current.Deadline) {
+		return Answer{}, ErrTooLate
+	}
+
+	a := Answer{
+		PlayerID: playerID,
+		Answer:   answer,
+		Correct:  g.current.Question.IsAnswerCorrect(answer),
+		Elapsed:  now.Sub(g.current.Started),
+	}
+
+	p.Answered++
+	if a.Correct {
+		a.Points = g.scoring.points(g.current.Question.Difficulty, a.Elapsed, g.timeLimit, p.Streak)
+
+		p.Correct++
+		p.Score += a.Points
+		p.Streak++
+		if p.Streak > p.BestStreak {
+			p.BestStreak = p.Streak
+		}
+	} else {
+		p.Streak = 0
+	}
+	a.Streak = p.Streak
+
+	g.answered[playerID] = true
+	g.answers = append(g.answers, a)
+
+	current := *g.current
+	g.emit(Event{Type: EventAnswerReceived, Question: &current, Answer: &a})
+
+	return a, nil
+}
+
+// AllAnswered reports whether every player answered the question in
+// progress.
+func (g *Game) AllAnswered() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return g.current != nil && len(g.answered) == len(g.players)
+}
+
+// Pause stops the clock of the question in progress. The answers are
+// rejected with ErrPaused until Resume is called.
+func (g *Game) Pause() error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.current == nil {
+		return ErrNoQuestion
+	}
+	if !g.paused.IsZero() {
+		return nil
+	}
+
+	g.paused = g.clock.Now()
+
+	current := *g.current
+	g.emit(Event{Type: EventPaused, Question: &current})
+
+	return nil
+}
+
+// Resume restarts the clock of the question in progress. Its deadline is
+// moved by the time it was paused.
+func (g *Game) Resume() error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.current == nil {
+		return ErrNoQuestion
+	}
+	if g.paused.IsZero() {
+		return nil
+	}
+
+	d := g.clock.Now().Sub(g.paused)
+	g.paused = time.Time{}
+
+	g.current.Started = g.current.Started.Add(d)
+	if !g.current.Deadline.IsZero() {
+		g.current.Deadline = g.current.Deadline.Add(d)
+	}
+
+	current := *g.current
+	g.emit(Event{Type: EventResumed, Question: &current})
+
+	return nil
+}
+
+// Paused reports whether the question in progress is paused.
+func (g *Game) Paused() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return !g.paused.IsZero()
+}
+
+// EndQuestion ends the question in progress. The players who did not
+// answer it lose their streaks.
+func (g *Game) EndQuestion() error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.current == nil {
+		return ErrNoQuestion
+	}
+
+	g.endQuestion()
+
+	return nil
+}
+
+// endQuestion ends the question in progress, if any. It must be called with
+// g.mu held.
+func (g *Game) endQuestion() {
+	if g.current == nil {
+		return
+	}
+
+	for id, p := range g.players {
+		if !g.answered[id] {
+			p.Streak = 0
+		}
+	}
+
+	current := *g.current
+	g.current = nil
+	g.paused = time.Time{}
+
+	g.emit(Event{
+		Type:        EventQuestionEnded,
+		Question:    &current,
+		Answers:     g.answers,
+		Leaderboard: g.leaderboard(),
+	})
+}
+
+// EndRound ends the round in progress and the question in progress, if
+// any. Ending the last round ends the game.
+func (g *Game) EndRound() error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if !g.inRound {
+		return ErrNoRound
+	}
+
+	g.endQuestion()
+
+	g.inRound = false
+	g.questions = nil
+	g.emit(Event{Type: EventRoundEnded, Leaderboard: g.leaderboard()})
+
+	if g.round >= len(g.config.Rounds) {
+		g.over = true
+		g.emit(Event{Type: EventGameEnded, Leaderboard: g.leaderboard()})
+	}
+
+	return nil
+}
+
+// Close ends the game and closes the events channel once the queued events
+// are delivered.
+func (g *Game) Close() error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.closed {
+		return nil
+	}
+
+	g.over = true
+	g.closed = true
+	g.cond.Broadcast()
+
+	return nil
+}
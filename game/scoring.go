@@ -0,0 +1,58 @@
+package game
+
+import (
+	"time"
+
+	"github.com/pinheirolucas/opentrivia"
+)
+
+// Scoring defines how many points a correct answer is worth.
+type Scoring struct {
+	// Points for a correct answer of each difficulty. Unknown
+	// difficulties are worth the easy points.
+	Points map[opentrivia.QuestionDifficulty]int
+
+	// SpeedBonus is the fraction of the points added for an instant
+	// answer. It decreases linearly to zero at the time limit.
+	SpeedBonus float64
+
+	// StreakBonus is the number of points added for each consecutive
+	// correct answer before the current one, up to MaxStreakBonus.
+	StreakBonus    int
+	MaxStreakBonus int
+}
+
+// DefaultScoring is the scoring used when the config has none.
+var DefaultScoring = Scoring{
+	Points: map[opentrivia.QuestionDifficulty]int{
+		opentrivia.QuestionDifficultyEasy:   100,
+		opentrivia.QuestionDifficultyMedium: 200,
+		opentrivia.QuestionDifficultyHard:   300,
+	},
+	SpeedBonus:     0.5,
+	StreakBonus:    10,
+	MaxStreakBonus: 50,
+}
+
+// points returns the points of a correct answer given elapsed after the
+// question started, with a time limit and the streak of the player before
+// the answer.
+func (s Scoring) points(difficulty string, elapsed, limit time.Duration, streak int) int {
+	base, ok := s.Points[opentrivia.QuestionDifficulty(difficulty)]
+	if !ok {
+		base = s.Points[opentrivia.QuestionDifficultyEasy]
+	}
+
+	points := base
+	if limit > 0 && elapsed < limit {
+		remaining := float64(limit-elapsed) / float64(limit)
+		points += int(float64(base) * s.SpeedBonus * remaining)
+	}
+
+	bonus := s.StreakBonus * streak
+	if s.MaxStreakBonus > 0 && bonus > s.MaxStreakBonus {
+		bonus = s.MaxStreakBonus
+	}
+
+	return points + bonus
+}
@@ -0,0 +1,185 @@
+package opentrivia
+
+import (
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// redacted replaces the tokens in the URLs reported to the loggers and
+// hooks.
+const redacted = "REDACTED"
+
+// Logger logs the API requests of a Client. It is satisfied by
+// *slog.Logger, and the arguments are alternating keys and values.
+type Logger interface {
+	Debug(msg string, args ...interface{})
+	Info(msg string, args ...interface{})
+	Warn(msg string, args ...interface{})
+	Error(msg string, args ...interface{})
+}
+
+// RequestInfo describes an API request sent by a Client. The token of the
+// URL is redacted.
+type RequestInfo struct {
+	Method string
+	URL    string
+	Start  time.Time
+
+	// Endpoint is the route of the request, such as api.php.
+	Endpoint string
+}
+
+// ResponseInfo describes the outcome of an API request.
+type ResponseInfo struct {
+	Request  RequestInfo
+	Duration time.Duration
+
+	// StatusCode is the HTTP status code, or 0 if there was no response.
+	StatusCode int
+
+	// ResponseCode is the response code of the Open Trivia API, or -1 if
+	// the response has none.
+	ResponseCode int
+
+	// Results is the number of questions, categories or tokens of the
+	// response.
+	Results int
+
+	// Err is the error returned by Client.Do.
+	Err error
+}
+
+// RequestHook observes the API requests of a Client.
+type RequestHook interface {
+	// BeforeRequest is called before the request is sent.
+	BeforeRequest(info RequestInfo)
+
+	// AfterResponse is called once the response is decoded, or the
+	// request failed.
+	AfterResponse(info ResponseInfo)
+}
+
+// RequestHookFuncs is an adapter to allow the use of ordinary functions as
+// a RequestHook. Nil functions are skipped.
+type RequestHookFuncs struct {
+	Before func(info RequestInfo)
+	After  func(info ResponseInfo)
+}
+
+// BeforeRequest calls h.Before(info).
+func (h RequestHookFuncs) BeforeRequest(info RequestInfo) {
+	if h.Before != nil {
+		h.Before(info)
+	}
+}
+
+// AfterResponse calls h.After(info).
+func (h RequestHookFuncs) AfterResponse(info ResponseInfo) {
+	if h.After != nil {
+		h.After(info)
+	}
+}
+
+// summarizer is implemented by the API responses to report their response
+// code and number of results.
+type summarizer interface {
+	summary() (code int, results int)
+}
+
+func (r *questionResponse) summary() (int, int) {
+	return int(r.ResponseCode), len(r.Results)
+}
+
+func (r *tokenResponse) summary() (int, int) {
+	if r.Token == "" {
+		return int(r.ResponseCode), 0
+	}
+
+	return int(r.ResponseCode), 1
+}
+
+func (r *categoryResponse) summary() (int, int) {
+	return -1, len(r.Categories)
+}
+
+func (c *Client) beforeRequest(info RequestInfo) {
+	if c.Hook != nil {
+		c.Hook.BeforeRequest(info)
+	}
+}
+
+func (c *Client) afterResponse(request RequestInfo, resp *http.Response, v interface{}, err error) {
+	info := ResponseInfo{
+		Request:      request,
+		Duration:     time.Since(request.Start),
+		ResponseCode: -1,
+		Err:          err,
+	}
+
+	if resp != nil {
+		info.StatusCode = resp.StatusCode
+	}
+	if s, ok := v.(summarizer); ok && err == nil {
+		info.ResponseCode, info.Results = s.summary()
+	}
+
+	c.recordRequest(info)
+
+	if c.Hook != nil {
+		c.Hook.AfterResponse(info)
+	}
+
+	if c.Logger == nil {
+		return
+	}
+
+	args := []interface{}{
+		"method", info.Request.Method,
+		"url", info.Request.URL,
+		"duration", info.Duration,
+		"status", info.StatusCode,
+		"response_code", info.ResponseCode,
+		"results", info.Results,
+	}
+
+	switch {
+	case err != nil:
+		c.Logger.Error("opentrivia: request failed", append(args, "error", err.Error())...)
+	case info.ResponseCode > int(responseCodeSuccess):
+		c.Logger.Warn("opentrivia: request", args...)
+	default:
+		c.Logger.Debug("opentrivia: request", args...)
+	}
+}
+
+// redactURL returns the URL with its token redacted.
+func redactURL(u *url.URL) string {
+	q := u.Query()
+	if q.Get("token") == "" {
+		return u.String()
+	}
+
+	q.Set("token", redacted)
+
+	cloned := *u
+	cloned.RawQuery = q.Encode()
+
+	return cloned.String()
+}
+
+// redactError redacts the token of the URL of a transport error, which is
+// logged, passed to the hooks and returned by Client.Do.
+func redactError(err error) error {
+	urlErr, ok := err.(*url.Error)
+	if !ok {
+		return err
+	}
+
+	u, parseErr := url.Parse(urlErr.URL)
+	if parseErr != nil {
+		return err
+	}
+
+	return &url.Error{Op: urlErr.Op, URL: redactURL(u), Err: urlErr.Err}
+}
@@ -0,0 +1,136 @@
+package live
+
+import (
+	"time"
+
+	"github.com/pinheirolucas/opentrivia/game"
+)
+
+// Types of the messages sent by the clients.
+const (
+	MessageAnswer = "answer"
+	MessageStart  = "start"
+	MessageSkip   = "skip"
+	MessagePause  = "pause"
+	MessageResume = "resume"
+)
+
+// Types of the messages sent by the server.
+const (
+	MessagePlayerJoined       = "player_joined"
+	MessagePlayerConnected    = "player_connected"
+	MessagePlayerDisconnected = "player_disconnected"
+	MessageRoundStarted       = "round_started"
+	MessageQuestion           = "question"
+	MessageAnswered           = "answered"
+	MessageResult             = "result"
+	MessagePaused             = "paused"
+	MessageResumed            = "resumed"
+	MessageQuestionEnded      = "question_ended"
+	MessageRoundEnded         = "round_ended"
+	MessageGameEnded          = "game_ended"
+	MessageError              = "error"
+)
+
+// Message is a message exchanged between the server and the clients. Only
+// the fields relevant to its type are set.
+type Message struct {
+	Type string `json:"type"`
+
+	// Seq is the position of the message in the log of the room. Clients
+	// resume from the last Seq they received after reconnecting.
+	Seq int `json:"seq,omitempty"`
+
+	// Round is the one-based number of the current round.
+	Round int `json:"round,omitempty"`
+
+	// PlayerID and Name identify the player of player messages, answers
+	// and results.
+	PlayerID string `json:"player_id,omitempty"`
+	Name     string `json:"name,omitempty"`
+
+	// Answer is the answer sent by a player.
+	Answer string `json:"answer,omitempty"`
+
+	// Question is the question being played, without its correct answer.
+	Question *Question `json:"question,omitempty"`
+
+	// CorrectAnswer is revealed when the question ends.
+	CorrectAnswer string `json:"correct_answer,omitempty"`
+
+	// Result is the scored answer of the player, sent only to that player.
+	Result *game.Answer `json:"result,omitempty"`
+
+	// Results are the scored answers of all players, sent when the question
+	// ends.
+	Results []game.Answer `json:"results,omitempty"`
+
+	Leaderboard []game.Player `json:"leaderboard,omitempty"`
+
+	Error string `json:"error,omitempty"`
+
+	// to restricts the message to a single player.
+	to string
+}
+
+// Question is a question as shown to the players.
+type Question struct {
+	Number     int       `json:"number"`
+	Total      int       `json:"total"`
+	Category   string    `json:"category"`
+	Type       string    `json:"type"`
+	Difficulty string    `json:"difficulty"`
+	Question   string    `json:"question"`
+	Answers    []string  `json:"answers"`
+	Deadline   time.Time `json:"deadline,omitempty"`
+}
+
+func newQuestion(q *game.Question) *Question {
+	return &Question{
+		Number:     q.Number,
+		Total:      q.Total,
+		Category:   q.Question.Category,
+		Type:       q.Question.Type,
+		Difficulty: q.Question.Difficulty,
+		Question:   q.Question.Question,
+		Answers:    q.Answers,
+		Deadline:   q.Deadline,
+	}
+}
+
+// eventMessage converts an event of the game to the message broadcast to
+// the clients.
+func eventMessage(e game.Event) Message {
+	m := Message{Round: e.Round}
+
+	switch e.Type {
+	case game.EventRoundStarted:
+		m.Type = MessageRoundStarted
+	case game.EventQuestionStarted:
+		m.Type = MessageQuestion
+		m.Question = newQuestion(e.Question)
+	case game.EventAnswerReceived:
+		m.Type = MessageAnswered
+		m.PlayerID = e.Answer.PlayerID
+	case game.EventPaused:
+		m.Type = MessagePaused
+		m.Question = newQuestion(e.Question)
+	case game.EventResumed:
+		m.Type = MessageResumed
+		m.Question = newQuestion(e.Question)
+	case game.EventQuestionEnded:
+		m.Type = MessageQuestionEnded
+		m.Question = newQuestion(e.Question)
+		m.CorrectAnswer = e.Question.Question.CorrectAnswer
+		m.Results = e.Answers
+		m.Leaderboard = e.Leaderboard
+	case game.EventRoundEnded:
+		m.Type = MessageRoundEnded
+		m.Leaderboard = e.Leaderboard
+	case game.EventGameEnded:
+		m.Type = MessageGameEnded
+		m.Leaderboard = e.Leaderboard
+	}
+
+	return m
+}
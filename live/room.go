@@ -0,0 +1,507 @@
+package live
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"github.com/pinheirolucas/opentrivia"
+	"github.com/pinheirolucas/opentrivia/game"
+	"github.com/pkg/errors"
+)
+
+// DefaultBreak is the time between the end of a question and the start of
+// the next one.
+const DefaultBreak = 5 * time.Second
+
+// Errors returned by the rooms.
+var (
+	ErrForbidden      = errors.New("live: forbidden")
+	ErrRoomClosed     = errors.New("live: room closed")
+	ErrUnknownMessage = errors.New("live: unknown message type")
+)
+
+// RoomConfig configures a room.
+type RoomConfig struct {
+	// Rounds is the number of rounds of the game. If zero, the game has a
+	// single round.
+	Rounds int
+
+	// Options are used to fetch the questions of each round. The token is
+	// managed by the room.
+	Options opentrivia.QuestionListOptions
+
+	// TimeLimit is the time to answer each question. If zero, a question
+	// ends when every player answered it or when the host skips it.
+	TimeLimit time.Duration
+
+	// Break is the time between questions. If zero, DefaultBreak is used.
+	Break time.Duration
+
+	// Scoring of the answers. If nil, game.DefaultScoring is used.
+	Scoring *game.Scoring
+}
+
+type roomState int
+
+const (
+	stateIdle roomState = iota
+	stateStarting
+	stateQuestion
+	stateBreak
+	stateOver
+)
+
+type member struct {
+	key         string
+	connections int
+}
+
+// Room is a live game. The questions are pushed to the connected clients
+// and every message is kept in a log, so clients that reconnect resume
+// where they stopped.
+//
+// All the rounds of a room share a single session token, so no question is
+// repeated during the game.
+type Room struct {
+	// ID identifies the room.
+	ID string
+
+	// HostKey authorizes the host controls: start, skip, pause and resume.
+	HostKey string
+
+	client *opentrivia.Client
+	config RoomConfig
+	game   *game.Game
+
+	tokenMu sync.Mutex
+	token   opentrivia.Token
+
+	mu        sync.Mutex
+	log       []Message
+	notify    chan struct{}
+	members   map[string]*member
+	state     roomState
+	paused    bool
+	timer     *time.Timer
+	gen       int
+	deadline  time.Time
+	remaining time.Duration
+	closed    bool
+
+	// active is the time of the last message.
+	active time.Time
+}
+
+// NewRoom returns a room whose questions are fetched with the client.
+func NewRoom(client *opentrivia.Client, config RoomConfig) *Room {
+	if config.Rounds <= 0 {
+		config.Rounds = 1
+	}
+	if config.Break == 0 {
+		config.Break = DefaultBreak
+	}
+
+	r := &Room{
+		ID:      randomID(),
+		HostKey: randomID(),
+		client:  client,
+		config:  config,
+		notify:  make(chan struct{}),
+		members: make(map[string]*member),
+		active:  time.Now(),
+	}
+
+	rounds := make([]game.Round, config.Rounds)
+	for i := range rounds {
+		options := config.Options
+		rounds[i].Options = &options
+	}
+
+	r.game = game.New(roomSource{r}, game.Config{
+		Rounds:    rounds,
+		TimeLimit: config.TimeLimit,
+		Scoring:   config.Scoring,
+	})
+	go r.pump(r.game.Events())
+
+	return r
+}
+
+// roomSource fetches the questions of the rounds with the token of the
+// room.
+type roomSource struct {
+	r *Room
+}
+
+func (s roomSource) List(options *opentrivia.QuestionListOptions) ([]opentrivia.Question, error) {
+	token, err := s.r.sessionToken()
+	if err != nil {
+		return nil, err
+	}
+
+	options.Token = token
+	options.AutoRefresh = true
+
+	return s.r.client.Question.List(options)
+}
+
+// Token returns the session token of the room, empty until the first round
+// starts.
+func (r *Room) Token() opentrivia.Token {
+	r.tokenMu.Lock()
+	defer r.tokenMu.Unlock()
+
+	return r.token
+}
+
+func (r *Room) sessionToken() (opentrivia.Token, error) {
+	r.tokenMu.Lock()
+	defer r.tokenMu.Unlock()
+
+	if r.token == "" {
+		t, err := r.client.Token.Create()
+		if err != nil {
+			return "", err
+		}
+
+		r.token = t
+	}
+
+	return r.token, nil
+}
+
+// pump appends the events of the game to the log.
+func (r *Room) pump(events <-chan game.Event) {
+	for e := range events {
+		r.mu.Lock()
+		r.append(eventMessage(e))
+		if e.Type == game.EventAnswerReceived {
+			r.append(Message{Type: MessageResult, Round: e.Round, PlayerID: e.Answer.PlayerID, Result: e.Answer, to: e.Answer.PlayerID})
+		}
+		r.mu.Unlock()
+	}
+
+	r.mu.Lock()
+	r.closed = true
+	close(r.notify)
+	r.mu.Unlock()
+}
+
+// append adds the message to the log and wakes up the clients waiting for
+// it. It must be called with r.mu held.
+func (r *Room) append(m Message) {
+	if r.closed {
+		return
+	}
+
+	m.Seq = len(r.log) + 1
+	r.log = append(r.log, m)
+	r.active = time.Now()
+
+	close(r.notify)
+	r.notify = make(chan struct{})
+}
+
+// Since returns the messages after seq visible to the player, or to
+// everyone if playerID is empty. The returned channel is closed when new
+// messages arrive. The boolean is false once the room is closed.
+func (r *Room) Since(seq int, playerID string) ([]Message, <-chan struct{}, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if seq < 0 {
+		seq = 0
+	}
+
+	var messages []Message
+	if seq < len(r.log) {
+		for _, m := range r.log[seq:] {
+			if m.to == "" || m.to == playerID {
+				messages = append(messages, m)
+			}
+		}
+	}
+
+	return messages, r.notify, !r.closed
+}
+
+// activity returns the time of the last message of the room, and whether
+// its game is over.
+func (r *Room) activity() (time.Time, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.active, r.state == stateOver || r.closed
+}
+
+// Join adds a player to the room and returns its id and the key used to
+// authenticate it, also when it reconnects.
+func (r *Room) Join(name string) (id, key string, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.closed {
+		return "", "", ErrRoomClosed
+	}
+
+	id = randomID()
+	key = randomID()
+	if err := r.game.AddPlayer(id, name); err != nil {
+		return "", "", err
+	}
+
+	r.members[id] = &member{key: key}
+	r.append(Message{Type: MessagePlayerJoined, PlayerID: id, Name: name})
+
+	return id, key, nil
+}
+
+// Authenticate reports whether the key belongs to the player.
+func (r *Room) Authenticate(playerID, key string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	m, ok := r.members[playerID]
+	return ok && key != "" && m.key == key
+}
+
+// connect and disconnect track the connections of the players, so the
+// other players know who is away.
+func (r *Room) connect(playerID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	m, ok := r.members[playerID]
+	if !ok {
+		return
+	}
+
+	m.connections++
+	if m.connections == 1 {
+		r.append(Message{Type: MessagePlayerConnected, PlayerID: playerID})
+	}
+}
+
+func (r *Room) disconnect(playerID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	m, ok := r.members[playerID]
+	if !ok || m.connections == 0 {
+		return
+	}
+
+	m.connections--
+	if m.connections == 0 {
+		r.append(Message{Type: MessagePlayerDisconnected, PlayerID: playerID})
+	}
+}
+
+// Leaderboard returns the players of the room ordered by score.
+func (r *Room) Leaderboard() []game.Player {
+	return r.game.Leaderboard()
+}
+
+// Answer records the answer of a player. When every player answered, the
+// question ends without waiting for the time limit.
+func (r *Room) Answer(playerID, answer string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, err := r.game.Answer(playerID, answer); err != nil {
+		return err
+	}
+
+	if r.state == stateQuestion && r.game.AllAnswered() {
+		r.finishQuestion()
+	}
+
+	return nil
+}
+
+// Start starts the next round. The questions are fetched in the
+// background and pushed to the clients when they arrive.
+func (r *Room) Start() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	switch r.state {
+	case stateOver:
+		return game.ErrGameOver
+	case stateIdle:
+	default:
+		return game.ErrRoundInProgress
+	}
+
+	r.state = stateStarting
+	go func() {
+		err := r.game.StartRound()
+
+		r.mu.Lock()
+		defer r.mu.Unlock()
+
+		if err != nil {
+			r.state = stateIdle
+			r.append(Message{Type: MessageError, Error: err.Error()})
+			return
+		}
+
+		r.next()
+	}()
+
+	return nil
+}
+
+// Skip ends the question in progress, or the break after it, and starts
+// the next question.
+func (r *Room) Skip() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	switch r.state {
+	case stateQuestion:
+		r.game.EndQuestion()
+	case stateBreak:
+	default:
+		return game.ErrNoQuestion
+	}
+
+	r.paused = false
+	r.next()
+
+	return nil
+}
+
+// Pause stops the clock of the question in progress or of the break after
+// it.
+func (r *Room) Pause() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.state != stateQuestion && r.state != stateBreak {
+		return game.ErrNoQuestion
+	}
+	if r.paused {
+		return nil
+	}
+
+	r.paused = true
+	r.remaining = 0
+	if r.timer != nil {
+		r.remaining = r.deadline.Sub(time.Now())
+	}
+	r.stop()
+
+	if r.state == stateQuestion {
+		return r.game.Pause()
+	}
+
+	r.append(Message{Type: MessagePaused})
+	return nil
+}
+
+// Resume restarts the clock stopped by Pause.
+func (r *Room) Resume() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.paused {
+		return nil
+	}
+	r.paused = false
+
+	if r.state == stateQuestion {
+		if r.config.TimeLimit > 0 {
+			r.arm(r.remaining, r.finishQuestion)
+		}
+
+		return r.game.Resume()
+	}
+
+	r.append(Message{Type: MessageResumed})
+	r.arm(r.remaining, r.next)
+
+	return nil
+}
+
+// Close ends the game and disconnects the clients.
+func (r *Room) Close() error {
+	r.mu.Lock()
+	r.stop()
+	r.state = stateOver
+	r.mu.Unlock()
+
+	return r.game.Close()
+}
+
+// next starts the next question, or ends the round when there are no more
+// questions. It must be called with r.mu held.
+func (r *Room) next() {
+	r.stop()
+
+	if _, err := r.game.NextQuestion(); err != nil {
+		r.game.EndRound()
+
+		r.state = stateIdle
+		if r.game.Over() {
+			r.state = stateOver
+		}
+
+		return
+	}
+
+	r.state = stateQuestion
+	if r.config.TimeLimit > 0 {
+		r.arm(r.config.TimeLimit, r.finishQuestion)
+	}
+}
+
+// finishQuestion ends the question in progress and starts the break. It
+// must be called with r.mu held.
+func (r *Room) finishQuestion() {
+	r.stop()
+	r.game.EndQuestion()
+
+	r.state = stateBreak
+	r.arm(r.config.Break, r.next)
+}
+
+// arm calls f, with r.mu held, after d. It must be called with r.mu held.
+func (r *Room) arm(d time.Duration, f func()) {
+	r.stop()
+
+	gen := r.gen
+	r.deadline = time.Now().Add(d)
+	r.timer = time.AfterFunc(d, func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+
+		// The timer may fire after it was stopped or replaced.
+		if r.gen != gen {
+			return
+		}
+
+		r.timer = nil
+		f()
+	})
+}
+
+// stop cancels the pending timer. It must be called with r.mu held.
+func (r *Room) stop() {
+	r.gen++
+	if r.timer != nil {
+		r.timer.Stop()
+		r.timer = nil
+	}
+}
+
+func randomID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+
+	return hex.EncodeToString(b)
+}
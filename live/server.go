@@ -0,0 +1,505 @@
+// Package live implements a real-time multiplayer quiz server on top of the
+// game package.
+//
+// The server hosts rooms. The host of a room creates it and controls the
+// game, the players join it and receive the questions as they are pushed,
+// over WebSocket, Server-Sent Events or long polling:
+//
+//	POST /rooms?amount=10&category=computer&time_limit=20s  create a room
+//	POST /rooms/{id}/join?name=Ana                           join a room
+//	GET  /rooms/{id}/ws?player_id=...&key=...&since=0        WebSocket
+//	GET  /rooms/{id}/events?player_id=...&key=...            Server-Sent Events
+//	GET  /rooms/{id}/poll?player_id=...&key=...&since=0      long polling
+//	POST /rooms/{id}/answer?player_id=...&key=...&answer=... answer
+//	POST /rooms/{id}/{start,skip,pause,resume}?key=...       host controls
+//
+// The rooms are removed once their game is over, or once they are idle, and
+// the WebSocket connections are only accepted from the origin of the server
+// unless CheckOrigin allows others.
+//
+// Every message has a sequence number. Clients that reconnect pass the last
+// one they received as since, or as the Last-Event-ID header of Server-Sent
+// Events, and receive the messages they missed.
+//
+// WebSocket clients may also send the answers and the host controls as
+// JSON messages, such as {"type":"answer","answer":"Paris"}.
+package live
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pinheirolucas/opentrivia"
+	"github.com/pinheirolucas/opentrivia/game"
+)
+
+// DefaultPollTimeout is the time a long polling request waits for new
+// messages.
+const DefaultPollTimeout = 25 * time.Second
+
+// DefaultIdleTimeout is the time a room is kept without any message.
+const DefaultIdleTimeout = 30 * time.Minute
+
+// DefaultFinishedTimeout is the time a room is kept once its game is over,
+// so the clients receive the final results.
+const DefaultFinishedTimeout = 5 * time.Minute
+
+// sweepInterval is the minimum interval between two removals of the
+// finished and idle rooms, which are made while serving the requests.
+const sweepInterval = time.Minute
+
+// Server is an http.Handler hosting live rooms.
+//
+// The fields of a Server must not be changed after it starts serving.
+type Server struct {
+	// PollTimeout is the time a long polling request waits for new
+	// messages. If zero, DefaultPollTimeout is used.
+	PollTimeout time.Duration
+
+	// IdleTimeout is the time a room is kept without any message. If zero,
+	// DefaultIdleTimeout is used.
+	IdleTimeout time.Duration
+
+	// FinishedTimeout is the time a room is kept once its game is over. If
+	// zero, DefaultFinishedTimeout is used.
+	FinishedTimeout time.Duration
+
+	// CheckOrigin reports whether a WebSocket handshake is accepted. If
+	// nil, SameOrigin is used.
+	CheckOrigin func(r *http.Request) bool
+
+	// Now returns the current time. If nil, time.Now is used.
+	Now func() time.Time
+
+	client *opentrivia.Client
+
+	mu        sync.Mutex
+	rooms     map[string]*Room
+	lastSweep time.Time
+}
+
+// NewServer returns a Server whose rooms fetch the questions with the
+// client.
+func NewServer(client *opentrivia.Client) *Server {
+	return &Server{
+		client: client,
+		rooms:  make(map[string]*Room),
+	}
+}
+
+// CreateRoom creates a room.
+func (s *Server) CreateRoom(config RoomConfig) *Room {
+	r := NewRoom(s.client, config)
+
+	s.mu.Lock()
+	s.rooms[r.ID] = r
+	s.mu.Unlock()
+
+	return r
+}
+
+// Room returns the room with the provided id.
+func (s *Server) Room(id string) (*Room, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r, ok := s.rooms[id]
+	return r, ok
+}
+
+// CloseRoom closes the room and forgets it.
+func (s *Server) CloseRoom(id string) error {
+	s.mu.Lock()
+	r, ok := s.rooms[id]
+	delete(s.rooms, id)
+	s.mu.Unlock()
+
+	if !ok {
+		return nil
+	}
+
+	return r.Close()
+}
+
+func (s *Server) now() time.Time {
+	if s.Now != nil {
+		return s.Now()
+	}
+
+	return time.Now()
+}
+
+// sweep closes and removes the finished and the idle rooms, unless they
+// were removed less than sweepInterval ago.
+func (s *Server) sweep() {
+	now := s.now()
+
+	idle := s.IdleTimeout
+	if idle == 0 {
+		idle = DefaultIdleTimeout
+	}
+	finished := s.FinishedTimeout
+	if finished == 0 {
+		finished = DefaultFinishedTimeout
+	}
+
+	s.mu.Lock()
+	if now.Sub(s.lastSweep) < sweepInterval {
+		s.mu.Unlock()
+		return
+	}
+	s.lastSweep = now
+
+	var expired []*Room
+	for id, r := range s.rooms {
+		active, over := r.activity()
+		if now.Sub(active) > idle || (over && now.Sub(active) > finished) {
+			delete(s.rooms, id)
+			expired = append(expired, r)
+		}
+	}
+	s.mu.Unlock()
+
+	for _, r := range expired {
+		r.Close()
+	}
+}
+
+// ServeHTTP implements the http.Handler interface.
+func (s *Server) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	s.sweep()
+
+	parts := strings.Split(strings.Trim(req.URL.Path, "/"), "/")
+	if len(parts) == 0 || parts[0] != "rooms" {
+		http.NotFound(w, req)
+		return
+	}
+
+	if len(parts) == 1 {
+		if req.Method != http.MethodPost {
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+
+		s.serveCreate(w, req)
+		return
+	}
+
+	r, ok := s.Room(parts[1])
+	if !ok || len(parts) != 3 {
+		http.NotFound(w, req)
+		return
+	}
+
+	switch parts[2] {
+	case "ws":
+		s.serveWebSocket(w, req, r)
+	case "events":
+		s.serveEvents(w, req, r)
+	case "poll":
+		s.servePoll(w, req, r)
+	case "join", MessageAnswer, MessageStart, MessageSkip, MessagePause, MessageResume:
+		if req.Method != http.MethodPost {
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+
+		if parts[2] == "join" {
+			s.serveJoin(w, req, r)
+			return
+		}
+
+		m := Message{Type: parts[2], Answer: req.FormValue("answer")}
+		if err := r.command(m, req.FormValue("player_id"), req.FormValue("key")); err != nil {
+			writeCommandError(w, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.NotFound(w, req)
+	}
+}
+
+func (s *Server) serveCreate(w http.ResponseWriter, req *http.Request) {
+	req.ParseForm()
+
+	options, err := opentrivia.ParseQuestionListOptions(req.Form)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	config := RoomConfig{Options: *options}
+	if v := req.Form.Get("rounds"); v != "" {
+		if config.Rounds, err = strconv.Atoi(v); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid rounds")
+			return
+		}
+	}
+	if v := req.Form.Get("time_limit"); v != "" {
+		if config.TimeLimit, err = time.ParseDuration(v); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid time_limit")
+			return
+		}
+	}
+	if v := req.Form.Get("break"); v != "" {
+		if config.Break, err = time.ParseDuration(v); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid break")
+			return
+		}
+	}
+
+	r := s.CreateRoom(config)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	writeJSON(w, map[string]string{"id": r.ID, "host_key": r.HostKey})
+}
+
+func (s *Server) serveJoin(w http.ResponseWriter, req *http.Request, r *Room) {
+	id, key, err := r.Join(req.FormValue("name"))
+	if err != nil {
+		writeCommandError(w, err)
+		return
+	}
+
+	writeJSON(w, map[string]string{"player_id": id, "key": key})
+}
+
+// subscriber returns the player of the request, empty for the host and the
+// spectators.
+func subscriber(req *http.Request, r *Room) (string, bool) {
+	playerID := req.FormValue("player_id")
+	if playerID == "" {
+		return "", true
+	}
+
+	return playerID, r.Authenticate(playerID, req.FormValue("key"))
+}
+
+// since returns the sequence number the client resumes from.
+func since(req *http.Request) int {
+	v := req.Header.Get("Last-Event-ID")
+	if v == "" {
+		v = req.FormValue("since")
+	}
+
+	n, _ := strconv.Atoi(v)
+	return n
+}
+
+func (s *Server) serveWebSocket(w http.ResponseWriter, req *http.Request, r *Room) {
+	playerID, ok := subscriber(req, r)
+	if !ok {
+		writeError(w, http.StatusForbidden, ErrForbidden.Error())
+		return
+	}
+	key := req.FormValue("key")
+	seq := since(req)
+
+	checkOrigin := s.CheckOrigin
+	if checkOrigin == nil {
+		checkOrigin = SameOrigin
+	}
+
+	conn, err := upgrade(w, req, checkOrigin)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	if playerID != "" {
+		r.connect(playerID)
+		defer r.disconnect(playerID)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+
+		for {
+			data, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+
+			var m Message
+			if err := json.Unmarshal(data, &m); err != nil {
+				writeMessage(conn, Message{Type: MessageError, Error: "invalid message"})
+				continue
+			}
+
+			if err := r.command(m, playerID, key); err != nil {
+				writeMessage(conn, Message{Type: MessageError, Error: err.Error()})
+			}
+		}
+	}()
+
+	for {
+		messages, notify, open := r.Since(seq, playerID)
+		for _, m := range messages {
+			if err := writeMessage(conn, m); err != nil {
+				return
+			}
+			seq = m.Seq
+		}
+		if !open {
+			return
+		}
+
+		select {
+		case <-notify:
+		case <-done:
+			return
+		}
+	}
+}
+
+func (s *Server) serveEvents(w http.ResponseWriter, req *http.Request, r *Room) {
+	playerID, ok := subscriber(req, r)
+	if !ok {
+		writeError(w, http.StatusForbidden, ErrForbidden.Error())
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, "streaming not supported")
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	if playerID != "" {
+		r.connect(playerID)
+		defer r.disconnect(playerID)
+	}
+
+	seq := since(req)
+	for {
+		messages, notify, open := r.Since(seq, playerID)
+		for _, m := range messages {
+			data, _ := json.Marshal(m)
+			fmt.Fprintf(w, "id: %d\ndata: %s\n\n", m.Seq, data)
+			seq = m.Seq
+		}
+		flusher.Flush()
+		if !open {
+			return
+		}
+
+		select {
+		case <-notify:
+		case <-req.Context().Done():
+			return
+		}
+	}
+}
+
+func (s *Server) servePoll(w http.ResponseWriter, req *http.Request, r *Room) {
+	playerID, ok := subscriber(req, r)
+	if !ok {
+		writeError(w, http.StatusForbidden, ErrForbidden.Error())
+		return
+	}
+
+	timeout := s.PollTimeout
+	if timeout == 0 {
+		timeout = DefaultPollTimeout
+	}
+
+	seq := since(req)
+	messages, notify, open := r.Since(seq, playerID)
+	if len(messages) == 0 && open {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+
+		select {
+		case <-notify:
+			messages, _, _ = r.Since(seq, playerID)
+		case <-timer.C:
+		case <-req.Context().Done():
+			return
+		}
+	}
+
+	if messages == nil {
+		messages = []Message{}
+	}
+
+	writeJSON(w, messages)
+}
+
+// command runs a command sent by a client.
+func (r *Room) command(m Message, playerID, key string) error {
+	switch m.Type {
+	case MessageAnswer:
+		if !r.Authenticate(playerID, key) {
+			return ErrForbidden
+		}
+
+		return r.Answer(playerID, m.Answer)
+	case MessageStart, MessageSkip, MessagePause, MessageResume:
+		if key == "" || key != r.HostKey {
+			return ErrForbidden
+		}
+	default:
+		return ErrUnknownMessage
+	}
+
+	switch m.Type {
+	case MessageStart:
+		return r.Start()
+	case MessageSkip:
+		return r.Skip()
+	case MessagePause:
+		return r.Pause()
+	default:
+		return r.Resume()
+	}
+}
+
+func writeMessage(conn *Conn, m Message) error {
+	data, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+
+	return conn.WriteMessage(data)
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	writeJSON(w, map[string]string{"error": message})
+}
+
+// writeCommandError answers with the status matching the error of a
+// command.
+func writeCommandError(w http.ResponseWriter, err error) {
+	status := http.StatusConflict
+	switch err {
+	case ErrForbidden:
+		status = http.StatusForbidden
+	case ErrUnknownMessage:
+		status = http.StatusBadRequest
+	case game.ErrUnknownPlayer:
+		status = http.StatusNotFound
+	}
+
+	writeError(w, status, err.Error())
+}
@@ -0,0 +1,179 @@
+package opentrivia
+
+import (
+	"expvar"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Names of the metrics emitted by the Client and the packages built on it.
+const (
+	// MetricRequests counts the API requests by endpoint and response_code.
+	// The response code is "none" for the responses without one, and
+	// "error" for the failed requests.
+	MetricRequests = "opentrivia_requests_total"
+
+	// MetricRequestDuration observes the latency of the API requests, in
+	// seconds, by endpoint.
+	MetricRequestDuration = "opentrivia_request_duration_seconds"
+
+	// MetricRetries counts the requests sent again, by endpoint and reason.
+	MetricRetries = "opentrivia_retries_total"
+
+	// MetricTokenCreates counts the tokens created.
+	MetricTokenCreates = "opentrivia_token_creates_total"
+
+	// MetricTokenRefreshes counts the tokens refreshed, by auto, which is
+	// "true" for the refreshes made by the AutoRefresh option.
+	MetricTokenRefreshes = "opentrivia_token_refreshes_total"
+
+	// MetricCacheHits counts the requests answered from a cache, by cache.
+	MetricCacheHits = "opentrivia_cache_hits_total"
+
+	// MetricCacheMisses counts the requests missing a cache, by cache.
+	MetricCacheMisses = "opentrivia_cache_misses_total"
+)
+
+// Labels are the dimensions of a metric, such as its endpoint.
+type Labels map[string]string
+
+// Metrics receives the counters and histograms of a Client. It is meant to
+// be adapted to a monitoring system.
+type Metrics interface {
+	// Add adds the value to the counter.
+	Add(name string, value float64, labels Labels)
+
+	// Observe records the value in the histogram.
+	Observe(name string, value float64, labels Labels)
+}
+
+// NopMetrics is a Metrics that discards everything.
+type NopMetrics struct{}
+
+// Add implements the Metrics interface.
+func (NopMetrics) Add(name string, value float64, labels Labels) {}
+
+// Observe implements the Metrics interface.
+func (NopMetrics) Observe(name string, value float64, labels Labels) {}
+
+// DefaultBuckets are the upper bounds of the histogram buckets of
+// ExpvarMetrics, suited to latencies in seconds.
+var DefaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// ExpvarMetrics is a Metrics kept in an expvar.Map. The keys are the names
+// of the metrics followed by their labels, such as:
+//
+//	opentrivia_requests_total{endpoint="api.php",response_code="0"}
+//
+// The counters are expvar.Float. The histograms are expvar.Map with the
+// count and the sum of the values, and the cumulative count of each bucket,
+// such as le_0.5.
+type ExpvarMetrics struct {
+	// Buckets are the upper bounds of the histogram buckets. If nil,
+	// DefaultBuckets is used.
+	Buckets []float64
+
+	mu   sync.Mutex
+	vars *expvar.Map
+}
+
+// NewExpvarMetrics returns an ExpvarMetrics published with the provided
+// name, which is served by the /debug/vars handler of the expvar package.
+// If name is empty, it is not published. Like expvar.Publish, it panics if
+// the name is already in use.
+func NewExpvarMetrics(name string) *ExpvarMetrics {
+	m := &ExpvarMetrics{vars: new(expvar.Map).Init()}
+	if name != "" {
+		expvar.Publish(name, m)
+	}
+
+	return m
+}
+
+// String implements the expvar.Var interface.
+func (m *ExpvarMetrics) String() string {
+	return m.vars.String()
+}
+
+// Get returns the variable of the metric with the labels, or nil if it was
+// never emitted.
+func (m *ExpvarMetrics) Get(name string, labels Labels) expvar.Var {
+	return m.vars.Get(metricKey(name, labels))
+}
+
+// Add implements the Metrics interface.
+func (m *ExpvarMetrics) Add(name string, value float64, labels Labels) {
+	m.vars.AddFloat(metricKey(name, labels), value)
+}
+
+// Observe implements the Metrics interface.
+func (m *ExpvarMetrics) Observe(name string, value float64, labels Labels) {
+	key := metricKey(name, labels)
+
+	m.mu.Lock()
+	h, ok := m.vars.Get(key).(*expvar.Map)
+	if !ok {
+		h = new(expvar.Map).Init()
+		m.vars.Set(key, h)
+	}
+	m.mu.Unlock()
+
+	buckets := m.Buckets
+	if buckets == nil {
+		buckets = DefaultBuckets
+	}
+
+	h.Add("count", 1)
+	h.AddFloat("sum", value)
+	for _, b := range buckets {
+		if value <= b {
+			h.Add("le_"+strconv.FormatFloat(b, 'g', -1, 64), 1)
+		}
+	}
+}
+
+// metricKey returns the name followed by the labels sorted by key.
+func metricKey(name string, labels Labels) string {
+	if len(labels) == 0 {
+		return name
+	}
+
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, len(keys))
+	for i, k := range keys {
+		pairs[i] = fmt.Sprintf("%s=%q", k, labels[k])
+	}
+
+	return name + "{" + strings.Join(pairs, ",") + "}"
+}
+
+func (c *Client) metrics() Metrics {
+	if c.Metrics == nil {
+		return NopMetrics{}
+	}
+
+	return c.Metrics
+}
+
+// recordRequest emits the metrics of an API request.
+func (c *Client) recordRequest(info ResponseInfo) {
+	code := strconv.Itoa(info.ResponseCode)
+	switch {
+	case info.Err != nil:
+		code = "error"
+	case info.ResponseCode < 0:
+		code = "none"
+	}
+
+	m := c.metrics()
+	m.Add(MetricRequests, 1, Labels{"endpoint": info.Request.Endpoint, "response_code": code})
+	m.Observe(MetricRequestDuration, info.Duration.Seconds(), Labels{"endpoint": info.Request.Endpoint})
+}
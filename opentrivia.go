@@ -1,9 +1,13 @@
 package opentrivia
 
 import (
-	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
 	"net/http"
 	"net/url"
+	"path"
+	"time"
 
 	"github.com/pkg/errors"
 )
@@ -18,8 +22,13 @@ const (
 	responseCodeInvalidParameter responseCode = 2
 	responseCodeTokenNotFound    responseCode = 3
 	responseCodeTokenEmpty       responseCode = 4
+	responseCodeRateLimit        responseCode = 5
 )
 
+// maxDrain is the most bytes read from a body left unread before its
+// connection is reused. Beyond it, the connection is closed.
+const maxDrain = 64 << 10
+
 const (
 	defaultBaseURL    = "https://opentdb.com/"
 	defaultAPIRoute   = "api.php"
@@ -27,8 +36,8 @@ const (
 )
 
 // DefaultClient is the default client for Open Trivia API.
-// It is the same as calling opentrivia.NewClient(nil).
-var DefaultClient = NewClient(nil)
+// It is the same as calling opentrivia.NewClient() without options.
+var DefaultClient, _ = NewClient()
 
 var (
 	// ErrInvalidParameter is returned when the Open Trivia API
@@ -38,8 +47,41 @@ var (
 	// ErrNoResults is returned when the Open Trivia API has no
 	// results.
 	ErrNoResults = errors.New("opentrivia: no results were found for the provided options")
+
+	// ErrRateLimit is returned when the Open Trivia API refuses a request
+	// because too many requests were sent from the same IP. It allows a
+	// request every 5 seconds.
+	ErrRateLimit = errors.New("opentrivia: too many requests, the rate limit was exceeded")
 )
 
+// UnknownResponseCodeError is returned when the Open Trivia API answers
+// with a response code unknown to this client.
+type UnknownResponseCodeError struct {
+	Code int
+}
+
+func (e *UnknownResponseCodeError) Error() string {
+	return fmt.Sprintf("opentrivia: unknown response code %d", e.Code)
+}
+
+// err returns the error matching a response code other than success.
+func (c responseCode) err() error {
+	switch c {
+	case responseCodeNoResults:
+		return ErrNoResults
+	case responseCodeInvalidParameter:
+		return ErrInvalidParameter
+	case responseCodeTokenNotFound:
+		return ErrTokenNotFound
+	case responseCodeTokenEmpty:
+		return ErrTokenEmpty
+	case responseCodeRateLimit:
+		return ErrRateLimit
+	}
+
+	return &UnknownResponseCodeError{Code: int(c)}
+}
+
 type service struct {
 	client *Client
 }
@@ -49,36 +91,94 @@ type Client struct {
 	client *http.Client
 	common service
 
+	listOptions   *QuestionListOptions
+	randomOptions *QuestionRandomOptions
+	limiter       *limiter
+	batchLimiter  *limiter
+
+	closeConnections bool
+	maxResponseSize  int64
+
 	// Base URL for API requests. Defaults to the public Open Trivia API.
 	// BaseURL should always be especified with a trailing slash.
 	BaseURL *url.URL
 
+	// UserAgent is the User-Agent header of the API requests. If empty,
+	// the default of the HTTP client is used.
+	UserAgent string
+
+	// Logger logs every API request. It is typically a *slog.Logger. If
+	// nil, nothing is logged.
+	Logger Logger
+
+	// Hook is called before every API request and after its response. If
+	// nil, no hook is called.
+	Hook RequestHook
+
+	// Metrics receives the counters and histograms of the API requests.
+	// NewClient sets it to NopMetrics.
+	Metrics Metrics
+
+	// Tracer starts the spans of the List, Random, Create and Refresh
+	// methods. NewClient sets it to NopTracer.
+	Tracer Tracer
+
 	// Services used for talking to different parts of the Open Trivia API.
-	// TODO: Add the services.
+	Category *CategoryService
 	Question *QuestionService
 	Token    *TokenService
 }
 
-// NewClient returns a new Open Trivia API client. If a nil httpClient is
-// provided, http.DefaultClient will be used.
-func NewClient(httpClient *http.Client) *Client {
-	if httpClient == nil {
-		cloned := *http.DefaultClient
-		httpClient = &cloned
-	}
-
+// NewClient returns a new Open Trivia API client configured by the
+// options. Without options, it talks to the public Open Trivia API with a
+// copy of http.DefaultClient. An error is returned if an option has an
+// invalid value.
+func NewClient(opts ...Option) (*Client, error) {
+	cloned := *http.DefaultClient
 	baseURL, _ := url.Parse(defaultBaseURL)
 
 	c := &Client{
-		client:  httpClient,
-		BaseURL: baseURL,
+		client:       &cloned,
+		batchLimiter: &limiter{interval: DefaultBatchRateLimit},
+		BaseURL:      baseURL,
+		Metrics:      NopMetrics{},
+		Tracer:       NopTracer{},
+	}
+
+	for _, opt := range opts {
+		if err := opt(c); err != nil {
+			return nil, err
+		}
 	}
 
 	c.common.client = c
+	c.Category = (*CategoryService)(&c.common)
 	c.Question = (*QuestionService)(&c.common)
 	c.Token = (*TokenService)(&c.common)
 
-	return c
+	return c, nil
+}
+
+// defaultListOptions returns a copy of the options used by List when it is
+// given nil options.
+func (c *Client) defaultListOptions() *QuestionListOptions {
+	options := *DefaultQuestionListOptions
+	if c.listOptions != nil {
+		options = *c.listOptions
+	}
+
+	return &options
+}
+
+// defaultRandomOptions returns a copy of the options used by Random when it
+// is given nil options.
+func (c *Client) defaultRandomOptions() *QuestionRandomOptions {
+	options := *DefaultQuestionRandomOptions
+	if c.randomOptions != nil {
+		options = *c.randomOptions
+	}
+
+	return &options
 }
 
 // NewRequest creates an API request.
@@ -97,34 +197,73 @@ func (c *Client) NewRequest(r string, q url.Values) (*http.Request, error) {
 		return nil, err
 	}
 
+	if c.UserAgent != "" {
+		req.Header.Set("User-Agent", c.UserAgent)
+	}
+
 	return req, nil
 }
 
 // Do sends an API request and returns an API response.The API response is
 // decoded and stored in the value pointed to by v, or returned as an error
 // if an API error has occurred.
+//
+// A response whose status is not 200 OK, whose content type is not JSON or
+// whose body is larger than the maximum response size is returned as an
+// *opentrivia.ResponseError.
 func (c *Client) Do(req *http.Request, v interface{}) (*http.Response, error) {
-	// Make sure to close the connection after replying to this request
-	req.Close = true
+	info := RequestInfo{
+		Method:   req.Method,
+		URL:      redactURL(req.URL),
+		Start:    time.Now(),
+		Endpoint: path.Base(req.URL.Path),
+	}
+	c.beforeRequest(info)
+
+	resp, err := c.do(req, v)
+	c.afterResponse(info, resp, v, err)
 
-	resp, err := c.client.Do(req)
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
 
-	if v != nil {
-		err = json.NewDecoder(resp.Body).Decode(v)
+	return resp, nil
+}
+
+// do sends the request and decodes the response. It returns the response
+// even if it could not be decoded, so it can be reported to the hooks.
+func (c *Client) do(req *http.Request, v interface{}) (*http.Response, error) {
+	if err := c.requestLimiter(req.Context()).wait(req.Context()); err != nil {
+		return nil, err
+	}
+
+	// The connections are kept alive for the next requests, unless the
+	// client was created WithCloseConnections.
+	if c.closeConnections {
+		req.Close = true
 	}
 
+	resp, err := c.client.Do(req)
 	if err != nil {
-		return nil, errors.Wrapf(
-			err,
-			"opentrivia: error reading response from %s %s",
-			req.Method,
-			req.URL.RequestURI(),
-		)
+		return nil, redactError(err)
+	}
+	defer drainAndClose(resp.Body)
+
+	max := c.maxResponseSize
+	if max <= 0 {
+		max = DefaultMaxResponseSize
+	}
+
+	if err := decodeResponse(req, resp, v, max); err != nil {
+		return resp, err
 	}
 
 	return resp, nil
 }
+
+// drainAndClose reads what is left of the body, up to maxDrain bytes, so
+// the connection can be reused, and closes it.
+func drainAndClose(body io.ReadCloser) {
+	io.CopyN(ioutil.Discard, body, maxDrain)
+	body.Close()
+}
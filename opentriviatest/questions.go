@@ -0,0 +1,78 @@
+package opentriviatest
+
+import (
+	"fmt"
+
+	"github.com/pinheirolucas/opentrivia"
+)
+
+// categoryIDs returns the categories known to the opentrivia package.
+func categoryIDs() []opentrivia.QuestionCategory {
+	var ids []opentrivia.QuestionCategory
+	for id := opentrivia.QuestionCategory(1); id < 255; id++ {
+		if id.Name() != "" {
+			ids = append(ids, id)
+		}
+	}
+
+	return ids
+}
+
+// Questions returns QuestionsPerGroup questions for each combination of
+// category, difficulty and type known to the opentrivia package.
+func Questions() []opentrivia.Question {
+	difficulties := []opentrivia.QuestionDifficulty{
+		opentrivia.QuestionDifficultyEasy,
+		opentrivia.QuestionDifficultyMedium,
+		opentrivia.QuestionDifficultyHard,
+	}
+
+	var questions []opentrivia.Question
+	for _, category := range categoryIDs() {
+		for _, difficulty := range difficulties {
+			for i := 0; i < QuestionsPerGroup; i++ {
+				questions = append(questions,
+					MultipleQuestion(category, difficulty, i),
+					TrueFalseQuestion(category, difficulty, i),
+				)
+			}
+		}
+	}
+
+	return questions
+}
+
+// MultipleQuestion returns the n-th multiple choice question of the
+// provided category and difficulty.
+func MultipleQuestion(category opentrivia.QuestionCategory, difficulty opentrivia.QuestionDifficulty, n int) opentrivia.Question {
+	return opentrivia.Question{
+		Category:      category.Name(),
+		Type:          string(opentrivia.QuestionTypeMultiple),
+		Difficulty:    string(difficulty),
+		Question:      fmt.Sprintf("%s multiple choice question #%d about %s?", difficulty, n, category.Name()),
+		CorrectAnswer: "Correct",
+		IncorrectAnswers: []string{
+			"Incorrect 1",
+			"Incorrect 2",
+			"Incorrect 3",
+		},
+	}
+}
+
+// TrueFalseQuestion returns the n-th true/false question of the provided
+// category and difficulty.
+func TrueFalseQuestion(category opentrivia.QuestionCategory, difficulty opentrivia.QuestionDifficulty, n int) opentrivia.Question {
+	correct, incorrect := "True", "False"
+	if n%2 == 1 {
+		correct, incorrect = incorrect, correct
+	}
+
+	return opentrivia.Question{
+		Category:         category.Name(),
+		Type:             string(opentrivia.QuestionTypeTrueFalse),
+		Difficulty:       string(difficulty),
+		Question:         fmt.Sprintf("%s true/false question #%d about %s.", difficulty, n, category.Name()),
+		CorrectAnswer:    correct,
+		IncorrectAnswers: []string{incorrect},
+	}
+}
@@ -0,0 +1,195 @@
+package opentriviatest
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// Mode is the mode of a RecordingTransport.
+type Mode int
+
+const (
+	// ModeRecord sends the requests to the network and writes them, along
+	// with their responses, to the cassette.
+	ModeRecord Mode = iota
+
+	// ModeReplay serves the responses from the cassette, without touching
+	// the network.
+	ModeReplay
+)
+
+// Interaction is a request and its response, as stored on a cassette.
+type Interaction struct {
+	Method string `json:"method"`
+	Path   string `json:"path"`
+	Query  string `json:"query"`
+
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header"`
+	Body       string      `json:"body"`
+}
+
+func (i *Interaction) key() string {
+	return i.Method + " " + i.Path + "?" + i.Query
+}
+
+type cassette struct {
+	Interactions []*Interaction `json:"interactions"`
+}
+
+// RecordingTransport is an http.RoundTripper that records the requests to
+// the Open Trivia API in a cassette file and replays them later. It plugs
+// into any client:
+//
+//	transport, err := opentriviatest.NewRecordingTransport("testdata/list.json", opentriviatest.ModeReplay)
+//	client, err := opentrivia.NewClient(opentrivia.WithHTTPClient(&http.Client{Transport: transport}))
+//
+// The requests are matched by method, path and query. The values of the
+// token parameter are ignored, since a new token is generated on every
+// recording. Requests with the same key are replayed in the recorded order.
+type RecordingTransport struct {
+	// Transport is used to send the requests in ModeRecord. If nil,
+	// http.DefaultTransport is used.
+	Transport http.RoundTripper
+
+	path string
+	mode Mode
+
+	mu       sync.Mutex
+	cassette cassette
+	replayed map[string]int
+}
+
+// NewRecordingTransport returns a RecordingTransport for the cassette at
+// path. In ModeReplay, the cassette must exist. In ModeRecord, the cassette
+// is overwritten.
+func NewRecordingTransport(path string, mode Mode) (*RecordingTransport, error) {
+	t := &RecordingTransport{
+		path:     path,
+		mode:     mode,
+		replayed: make(map[string]int),
+	}
+
+	if mode == ModeReplay {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, errors.Wrap(err, "opentriviatest: error reading cassette")
+		}
+		if err := json.Unmarshal(data, &t.cassette); err != nil {
+			return nil, errors.Wrapf(err, "opentriviatest: error decoding cassette %s", path)
+		}
+	}
+
+	return t, nil
+}
+
+// RoundTrip implements the http.RoundTripper interface.
+func (t *RecordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.mode == ModeReplay {
+		return t.replay(req)
+	}
+
+	return t.record(req)
+}
+
+func (t *RecordingTransport) replay(req *http.Request) (*http.Response, error) {
+	key := requestInteraction(req).key()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	n := t.replayed[key]
+	for _, i := range t.cassette.Interactions {
+		if i.key() != key {
+			continue
+		}
+		if n > 0 {
+			n--
+			continue
+		}
+
+		t.replayed[key]++
+		return &http.Response{
+			Status:        http.StatusText(i.StatusCode),
+			StatusCode:    i.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        i.Header,
+			Body:          ioutil.NopCloser(bytes.NewBufferString(i.Body)),
+			ContentLength: int64(len(i.Body)),
+			Request:       req,
+		}, nil
+	}
+
+	return nil, errors.Errorf("opentriviatest: no recorded interaction left for %s", key)
+}
+
+func (t *RecordingTransport) record(req *http.Request) (*http.Response, error) {
+	transport := t.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	i := requestInteraction(req)
+	i.StatusCode = resp.StatusCode
+	i.Header = resp.Header
+	i.Body = string(body)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.cassette.Interactions = append(t.cassette.Interactions, i)
+	if err := t.save(); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// save writes the cassette. It must be called with t.mu held.
+func (t *RecordingTransport) save() error {
+	data, err := json.MarshalIndent(&t.cassette, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := ioutil.WriteFile(t.path, data, os.FileMode(0644)); err != nil {
+		return errors.Wrap(err, "opentriviatest: error writing cassette")
+	}
+
+	return nil
+}
+
+// requestInteraction returns an interaction holding the normalized request.
+func requestInteraction(req *http.Request) *Interaction {
+	q := req.URL.Query()
+	if _, ok := q["token"]; ok {
+		q.Set("token", "")
+	}
+
+	return &Interaction{
+		Method: req.Method,
+		Path:   req.URL.Path,
+		// Encode sorts the values by key.
+		Query: q.Encode(),
+	}
+}
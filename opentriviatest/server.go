@@ -0,0 +1,179 @@
+// Package opentriviatest provides a fake Open Trivia API for testing.
+//
+// The fake server implements api.php, api_token.php, api_category.php,
+// api_count.php and api_count_global.php with the same semantics as the
+// Open Trivia API, so tests can run offline:
+//
+//	server := opentriviatest.NewServer()
+//	defer server.Close()
+//
+//	client := server.Client()
+//	questions, err := client.Question.List(nil)
+package opentriviatest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"time"
+
+	"github.com/pinheirolucas/opentrivia"
+	"github.com/pinheirolucas/opentrivia/server"
+)
+
+// Response codes of the Open Trivia API.
+const (
+	ResponseCodeSuccess          = server.ResponseCodeSuccess
+	ResponseCodeNoResults        = server.ResponseCodeNoResults
+	ResponseCodeInvalidParameter = server.ResponseCodeInvalidParameter
+	ResponseCodeTokenNotFound    = server.ResponseCodeTokenNotFound
+	ResponseCodeTokenEmpty       = server.ResponseCodeTokenEmpty
+	ResponseCodeRateLimit        = server.ResponseCodeRateLimit
+)
+
+// QuestionsPerGroup is the number of questions generated by Questions for
+// each combination of category, difficulty and type.
+const QuestionsPerGroup = 25
+
+// Server is a fake Open Trivia API listening on a local address. It is
+// backed by a server.Server, to which it adds fault injection.
+type Server struct {
+	*httptest.Server
+
+	store *server.MemoryStore
+
+	mu           sync.Mutex
+	responseCode int
+	latency      time.Duration
+	rateLimit    int
+	ratePeriod   time.Duration
+	requests     []time.Time
+	count        int
+}
+
+// NewServer starts and returns a new Server serving the questions returned
+// by Questions. The caller should call Close when finished, to shut it
+// down.
+func NewServer() *Server {
+	// The questions of Questions have no custom categories, so the store
+	// is always created.
+	store, _ := server.NewMemoryStore(Questions())
+
+	s := &Server{
+		store:        store,
+		responseCode: -1,
+	}
+
+	s.Server = httptest.NewServer(s.intercept(server.NewServer(s.store)))
+
+	return s
+}
+
+// Client returns a client pointed at the server.
+func (s *Server) Client() *opentrivia.Client {
+	c, _ := opentrivia.NewClient(opentrivia.WithBaseURL(s.URL + "/"))
+
+	return c
+}
+
+// SetQuestions replaces the questions served by the server. It panics if
+// the questions have more categories than server.MemoryStore can number.
+func (s *Server) SetQuestions(questions []opentrivia.Question) {
+	if err := s.store.Set(questions); err != nil {
+		panic(err)
+	}
+}
+
+// SetResponseCode makes api.php and api_token.php answer every following
+// request with the provided response code and no results. A negative code
+// restores the regular behavior.
+func (s *Server) SetResponseCode(code int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.responseCode = code
+}
+
+// SetLatency delays every following response by d.
+func (s *Server) SetLatency(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.latency = d
+}
+
+// SetRateLimit allows n requests per period. The requests above the limit
+// are answered with ResponseCodeRateLimit, like the Open Trivia API does.
+// A zero n disables the rate limit.
+func (s *Server) SetRateLimit(n int, period time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.rateLimit = n
+	s.ratePeriod = period
+	s.requests = nil
+}
+
+// Requests returns the number of requests received by the server.
+func (s *Server) Requests() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.count
+}
+
+// intercept applies the latency, the rate limit and the forced response
+// code before the requests reach the handlers.
+func (s *Server) intercept(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		s.count++
+		latency := s.latency
+		limited := s.limited(time.Now())
+		code := s.responseCode
+		s.mu.Unlock()
+
+		if latency > 0 {
+			time.Sleep(latency)
+		}
+
+		forced := r.URL.Path == "/api.php" || r.URL.Path == "/api_token.php"
+		switch {
+		case limited:
+			writeJSON(w, map[string]interface{}{"response_code": ResponseCodeRateLimit, "results": []opentrivia.Question{}})
+		case forced && code >= 0:
+			writeJSON(w, map[string]interface{}{"response_code": code, "results": []opentrivia.Question{}})
+		default:
+			next.ServeHTTP(w, r)
+		}
+	})
+}
+
+// limited records a request made at now and reports if it is above the
+// rate limit. It must be called with s.mu held.
+func (s *Server) limited(now time.Time) bool {
+	if s.rateLimit <= 0 {
+		return false
+	}
+
+	recent := s.requests[:0]
+	for _, t := range s.requests {
+		if now.Sub(t) < s.ratePeriod {
+			recent = append(recent, t)
+		}
+	}
+	s.requests = recent
+
+	if len(s.requests) >= s.rateLimit {
+		return true
+	}
+
+	s.requests = append(s.requests, now)
+	return false
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,117 @@
+package opentriviatest
+
+import (
+	"context"
+	"sync"
+
+	"github.com/pinheirolucas/opentrivia"
+)
+
+// RecordedSpan is a span recorded by a SpanRecorder.
+type RecordedSpan struct {
+	Name string
+
+	// Parent is the span carried by the context the span was started
+	// with, or nil.
+	Parent *RecordedSpan
+
+	mu         sync.Mutex
+	attributes map[string]interface{}
+	errors     []error
+	ended      bool
+}
+
+// SetAttribute implements the opentrivia.Span interface.
+func (s *RecordedSpan) SetAttribute(key string, value interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.attributes[key] = value
+}
+
+// RecordError implements the opentrivia.Span interface.
+func (s *RecordedSpan) RecordError(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.errors = append(s.errors, err)
+}
+
+// End implements the opentrivia.Span interface.
+func (s *RecordedSpan) End() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.ended = true
+}
+
+// Attribute returns the value of an attribute, or nil if it was not set.
+func (s *RecordedSpan) Attribute(key string) interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.attributes[key]
+}
+
+// Errors returns the errors recorded by the span.
+func (s *RecordedSpan) Errors() []error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]error(nil), s.errors...)
+}
+
+// Ended reports if the span was ended.
+func (s *RecordedSpan) Ended() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.ended
+}
+
+type spanKey struct{}
+
+// SpanRecorder is an opentrivia.Tracer keeping the spans in memory, so tests
+// can inspect them.
+type SpanRecorder struct {
+	mu    sync.Mutex
+	spans []*RecordedSpan
+}
+
+// NewSpanRecorder returns an empty SpanRecorder.
+func NewSpanRecorder() *SpanRecorder {
+	return &SpanRecorder{}
+}
+
+// Start implements the opentrivia.Tracer interface.
+func (r *SpanRecorder) Start(ctx context.Context, name string) (context.Context, opentrivia.Span) {
+	parent, _ := ctx.Value(spanKey{}).(*RecordedSpan)
+
+	s := &RecordedSpan{
+		Name:       name,
+		Parent:     parent,
+		attributes: make(map[string]interface{}),
+	}
+
+	r.mu.Lock()
+	r.spans = append(r.spans, s)
+	r.mu.Unlock()
+
+	return context.WithValue(ctx, spanKey{}, s), s
+}
+
+// Spans returns the spans started so far, in the order they were started.
+func (r *SpanRecorder) Spans() []*RecordedSpan {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]*RecordedSpan(nil), r.spans...)
+}
+
+// Reset forgets the spans started so far.
+func (r *SpanRecorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.spans = nil
+}
@@ -0,0 +1,215 @@
+package opentrivia
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Option configures a Client created by NewClient.
+type Option func(c *Client) error
+
+// WithBaseURL sets the base URL of the API requests. It must be an
+// absolute URL with a trailing slash, such as https://opentdb.com/.
+func WithBaseURL(rawURL string) Option {
+	return func(c *Client) error {
+		u, err := url.Parse(rawURL)
+		if err != nil {
+			return errors.Wrap(err, "opentrivia: invalid base URL")
+		}
+
+		if err := validateBaseURL(u); err != nil {
+			return err
+		}
+
+		c.BaseURL = u
+		return nil
+	}
+}
+
+// WithHTTPClient sets the HTTP client sending the API requests.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) error {
+		if httpClient == nil {
+			return errors.New("opentrivia: the HTTP client is nil")
+		}
+
+		c.client = httpClient
+		return nil
+	}
+}
+
+// WithUserAgent sets the User-Agent header of the API requests.
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) error {
+		if strings.TrimSpace(userAgent) == "" {
+			return errors.New("opentrivia: the user agent is empty")
+		}
+
+		c.UserAgent = userAgent
+		return nil
+	}
+}
+
+// WithDefaultListOptions sets the options used by QuestionService List when
+// it is given nil options, instead of DefaultQuestionListOptions. Their
+// Limit is also used when the provided options have none.
+func WithDefaultListOptions(options QuestionListOptions) Option {
+	return func(c *Client) error {
+		if options.Limit == 0 {
+			options.Limit = DefaultQuestionListOptions.Limit
+		}
+
+		if err := options.Validate(); err != nil {
+			return err
+		}
+
+		c.listOptions = &options
+		return nil
+	}
+}
+
+// WithDefaultRandomOptions sets the options used by QuestionService Random
+// when it is given nil options, instead of DefaultQuestionRandomOptions.
+func WithDefaultRandomOptions(options QuestionRandomOptions) Option {
+	return func(c *Client) error {
+		if err := options.Validate(); err != nil {
+			return err
+		}
+
+		c.randomOptions = &options
+		return nil
+	}
+}
+
+// WithRateLimit spaces the API requests of the client by at least interval.
+// The Open Trivia API allows a request every 5 seconds for each IP. A zero
+// interval disables the rate limit. Without it, only the requests of
+// ListBatch are spaced, by DefaultBatchRateLimit.
+func WithRateLimit(interval time.Duration) Option {
+	return func(c *Client) error {
+		if interval < 0 {
+			return errors.New("opentrivia: the rate limit interval is negative")
+		}
+
+		c.limiter = &limiter{interval: interval}
+		return nil
+	}
+}
+
+// WithCloseConnections closes the connection after each request, instead of
+// keeping it alive for the next requests.
+func WithCloseConnections() Option {
+	return func(c *Client) error {
+		c.closeConnections = true
+		return nil
+	}
+}
+
+// WithMaxResponseSize sets the largest response body read by the client,
+// instead of DefaultMaxResponseSize.
+func WithMaxResponseSize(n int64) Option {
+	return func(c *Client) error {
+		if n <= 0 {
+			return errors.New("opentrivia: the max response size must be positive")
+		}
+
+		c.maxResponseSize = n
+		return nil
+	}
+}
+
+// WithLogger sets the Logger of the client.
+func WithLogger(logger Logger) Option {
+	return func(c *Client) error {
+		c.Logger = logger
+		return nil
+	}
+}
+
+// WithRequestHook sets the RequestHook of the client.
+func WithRequestHook(hook RequestHook) Option {
+	return func(c *Client) error {
+		c.Hook = hook
+		return nil
+	}
+}
+
+// WithMetrics sets the Metrics of the client.
+func WithMetrics(metrics Metrics) Option {
+	return func(c *Client) error {
+		if metrics == nil {
+			metrics = NopMetrics{}
+		}
+
+		c.Metrics = metrics
+		return nil
+	}
+}
+
+// WithTracer sets the Tracer of the client.
+func WithTracer(tracer Tracer) Option {
+	return func(c *Client) error {
+		if tracer == nil {
+			tracer = NopTracer{}
+		}
+
+		c.Tracer = tracer
+		return nil
+	}
+}
+
+func validateBaseURL(u *url.URL) error {
+	if !u.IsAbs() || u.Host == "" {
+		return errors.Errorf("opentrivia: the base URL %q is not absolute", u)
+	}
+	if !strings.HasSuffix(u.Path, "/") {
+		return errors.Errorf("opentrivia: the base URL %q must have a trailing slash", u)
+	}
+
+	return nil
+}
+
+// limiter spaces the requests by at least interval.
+type limiter struct {
+	interval time.Duration
+
+	mu   sync.Mutex
+	next time.Time
+}
+
+// wait blocks until the next request is allowed, or the context is done.
+func (l *limiter) wait(ctx context.Context) error {
+	if l == nil || l.interval <= 0 {
+		return nil
+	}
+
+	l.mu.Lock()
+	now := time.Now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	slot := l.next
+	l.next = slot.Add(l.interval)
+	l.mu.Unlock()
+
+	d := slot.Sub(now)
+	if d <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
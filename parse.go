@@ -0,0 +1,352 @@
+package opentrivia
+
+import (
+	"encoding/json"
+	"net/url"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/pkg/errors"
+)
+
+// questionCategoryNames are the names used by the Open Trivia API for each
+// category.
+var questionCategoryNames = map[QuestionCategory]string{
+	QuestionCategoryGeneralKnowledge: "General Knowledge",
+	QuestionCategoryBook:             "Entertainment: Books",
+	QuestionCategoryFilm:             "Entertainment: Film",
+	QuestionCategoryMusic:            "Entertainment: Music",
+	QuestionCategoryMusical:          "Entertainment: Musicals & Theatres",
+	QuestionCategoryTelevision:       "Entertainment: Television",
+	QuestionCategoryVideoGame:        "Entertainment: Video Games",
+	QuestionCategoryBoardGame:        "Entertainment: Board Games",
+	QuestionCategoryNature:           "Science & Nature",
+	QuestionCategoryComputer:         "Science: Computers",
+	QuestionCategoryMath:             "Science: Mathematics",
+	QuestionCategoryMythology:        "Mythology",
+	QuestionCategorySport:            "Sports",
+	QuestionCategoryGeography:        "Geography",
+	QuestionCategoryHistory:          "History",
+	QuestionCategoryPolitics:         "Politics",
+	QuestionCategoryArt:              "Art",
+	QuestionCategoryCelebrity:        "Celebrities",
+	QuestionCategoryAnimal:           "Animals",
+	QuestionCategoryVehicles:         "Vehicles",
+	QuestionCategoryComic:            "Entertainment: Comics",
+	QuestionCategoryGadget:           "Science: Gadgets",
+	QuestionCategoryAnime:            "Entertainment: Japanese Anime & Manga",
+	QuestionCategoryCartoon:          "Entertainment: Cartoon & Animations",
+}
+
+// questionCategoryAliases are the normalized aliases accepted by
+// ParseQuestionCategory besides the IDs and the canonical names.
+var questionCategoryAliases = map[string]QuestionCategory{
+	"general":          QuestionCategoryGeneralKnowledge,
+	"generalknowledge": QuestionCategoryGeneralKnowledge,
+	"book":             QuestionCategoryBook,
+	"books":            QuestionCategoryBook,
+	"film":             QuestionCategoryFilm,
+	"films":            QuestionCategoryFilm,
+	"movie":            QuestionCategoryFilm,
+	"movies":           QuestionCategoryFilm,
+	"music":            QuestionCategoryMusic,
+	"musical":          QuestionCategoryMusical,
+	"musicals":         QuestionCategoryMusical,
+	"theatre":          QuestionCategoryMusical,
+	"theater":          QuestionCategoryMusical,
+	"television":       QuestionCategoryTelevision,
+	"tv":               QuestionCategoryTelevision,
+	"videogame":        QuestionCategoryVideoGame,
+	"videogames":       QuestionCategoryVideoGame,
+	"games":            QuestionCategoryVideoGame,
+	"boardgame":        QuestionCategoryBoardGame,
+	"boardgames":       QuestionCategoryBoardGame,
+	"nature":           QuestionCategoryNature,
+	"science":          QuestionCategoryNature,
+	"computer":         QuestionCategoryComputer,
+	"computers":        QuestionCategoryComputer,
+	"math":             QuestionCategoryMath,
+	"maths":            QuestionCategoryMath,
+	"mathematics":      QuestionCategoryMath,
+	"mythology":        QuestionCategoryMythology,
+	"sport":            QuestionCategorySport,
+	"sports":           QuestionCategorySport,
+	"geography":        QuestionCategoryGeography,
+	"history":          QuestionCategoryHistory,
+	"politics":         QuestionCategoryPolitics,
+	"art":              QuestionCategoryArt,
+	"celebrity":        QuestionCategoryCelebrity,
+	"celebrities":      QuestionCategoryCelebrity,
+	"animal":           QuestionCategoryAnimal,
+	"animals":          QuestionCategoryAnimal,
+	"vehicle":          QuestionCategoryVehicles,
+	"vehicles":         QuestionCategoryVehicles,
+	"comic":            QuestionCategoryComic,
+	"comics":           QuestionCategoryComic,
+	"gadget":           QuestionCategoryGadget,
+	"gadgets":          QuestionCategoryGadget,
+	"anime":            QuestionCategoryAnime,
+	"manga":            QuestionCategoryAnime,
+	"cartoon":          QuestionCategoryCartoon,
+	"cartoons":         QuestionCategoryCartoon,
+	"animation":        QuestionCategoryCartoon,
+	"animations":       QuestionCategoryCartoon,
+}
+
+var questionDifficultyAliases = map[string]QuestionDifficulty{
+	"e":      QuestionDifficultyEasy,
+	"easy":   QuestionDifficultyEasy,
+	"m":      QuestionDifficultyMedium,
+	"medium": QuestionDifficultyMedium,
+	"normal": QuestionDifficultyMedium,
+	"h":      QuestionDifficultyHard,
+	"hard":   QuestionDifficultyHard,
+}
+
+var questionTypeAliases = map[string]QuestionType{
+	"multiple":       QuestionTypeMultiple,
+	"multiplechoice": QuestionTypeMultiple,
+	"choice":         QuestionTypeMultiple,
+	"mc":             QuestionTypeMultiple,
+	"boolean":        QuestionTypeTrueFalse,
+	"bool":           QuestionTypeTrueFalse,
+	"truefalse":      QuestionTypeTrueFalse,
+	"tf":             QuestionTypeTrueFalse,
+}
+
+// normalize lowercases s and strips everything but letters and digits, so
+// "Entertainment: Video Games" and "entertainment-video-games" are equal.
+func normalize(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+
+		return -1
+	}, s)
+}
+
+// Name returns the name used by the Open Trivia API for the category, or
+// an empty string if the category is unknown to this client.
+func (c QuestionCategory) Name() string {
+	return questionCategoryNames[c]
+}
+
+// ParseQuestionCategory parses a category from its ID, its Open Trivia API
+// name or one of its aliases, such as "computers" or "tv".
+//
+// Numeric IDs are accepted even if the category is unknown to this client.
+func ParseQuestionCategory(s string) (QuestionCategory, error) {
+	if id, err := strconv.ParseUint(strings.TrimSpace(s), 10, 8); err == nil {
+		return QuestionCategory(id), nil
+	}
+
+	n := normalize(s)
+	if c, ok := questionCategoryAliases[n]; ok {
+		return c, nil
+	}
+
+	for c, name := range questionCategoryNames {
+		if normalize(name) == n {
+			return c, nil
+		}
+	}
+
+	return 0, errors.Errorf("opentrivia: unknown category %q", s)
+}
+
+// ParseQuestionDifficulty parses a difficulty from its name or one of its
+// aliases, such as "e" or "normal".
+func ParseQuestionDifficulty(s string) (QuestionDifficulty, error) {
+	if d, ok := questionDifficultyAliases[normalize(s)]; ok {
+		return d, nil
+	}
+
+	return "", errors.Errorf("opentrivia: unknown difficulty %q", s)
+}
+
+// ParseQuestionType parses a question type from its name or one of its
+// aliases, such as "true/false" or "mc".
+func ParseQuestionType(s string) (QuestionType, error) {
+	if t, ok := questionTypeAliases[normalize(s)]; ok {
+		return t, nil
+	}
+
+	return "", errors.Errorf("opentrivia: unknown question type %q", s)
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface using
+// ParseQuestionCategory. An empty text is the zero value, meaning any.
+func (c *QuestionCategory) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*c = 0
+		return nil
+	}
+
+	v, err := ParseQuestionCategory(string(text))
+	if err != nil {
+		return err
+	}
+
+	*c = v
+	return nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface using
+// ParseQuestionDifficulty. An empty text is the zero value, meaning any.
+func (d *QuestionDifficulty) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*d = ""
+		return nil
+	}
+
+	v, err := ParseQuestionDifficulty(string(text))
+	if err != nil {
+		return err
+	}
+
+	*d = v
+	return nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface using
+// ParseQuestionType. An empty text is the zero value, meaning any.
+func (t *QuestionType) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*t = ""
+		return nil
+	}
+
+	v, err := ParseQuestionType(string(text))
+	if err != nil {
+		return err
+	}
+
+	*t = v
+	return nil
+}
+
+type optionValues struct {
+	v      url.Values
+	errors []*FieldError
+}
+
+func (p *optionValues) fail(field, value string, err error) {
+	p.errors = append(p.errors, &FieldError{
+		Field:  field,
+		Value:  value,
+		Reason: strings.TrimPrefix(err.Error(), "opentrivia: "),
+	})
+}
+
+func (p *optionValues) category(c *QuestionCategory) {
+	if s := p.v.Get("category"); s != "" {
+		if err := c.UnmarshalText([]byte(s)); err != nil {
+			p.fail("Category", s, err)
+		}
+	}
+}
+
+func (p *optionValues) difficulty(d *QuestionDifficulty) {
+	if s := p.v.Get("difficulty"); s != "" {
+		if err := d.UnmarshalText([]byte(s)); err != nil {
+			p.fail("Difficulty", s, err)
+		}
+	}
+}
+
+func (p *optionValues) questionType(t *QuestionType) {
+	if s := p.v.Get("type"); s != "" {
+		if err := t.UnmarshalText([]byte(s)); err != nil {
+			p.fail("Type", s, err)
+		}
+	}
+}
+
+func (p *optionValues) err() error {
+	if len(p.errors) == 0 {
+		return nil
+	}
+
+	return &ValidationError{Errors: p.errors}
+}
+
+// ParseQuestionListOptions builds the options for QuestionService List
+// method from query parameters such as
+// "amount=20&category=computers&difficulty=hard&type=multiple".
+//
+// The parameters use the same names as the Open Trivia API, so the values
+// encoded from the options can be parsed back. If any value cannot be
+// parsed, ParseQuestionListOptions returns an *opentrivia.ValidationError.
+func ParseQuestionListOptions(v url.Values) (*QuestionListOptions, error) {
+	options := &QuestionListOptions{
+		Token: Token(v.Get("token")),
+	}
+
+	p := &optionValues{v: v}
+	p.category(&options.Category)
+	p.difficulty(&options.Difficulty)
+	if s := v.Get("amount"); s != "" {
+		limit, err := strconv.Atoi(s)
+		switch {
+		case err != nil:
+			p.fail("Limit", s, errors.New("must be a number"))
+		case limit < 1 || limit > 50:
+			p.fail("Limit", s, errors.New("must be between 1 and 50"))
+		default:
+			options.Limit = uint8(limit)
+		}
+	}
+	p.questionType(&options.Type)
+
+	if err := p.err(); err != nil {
+		return nil, err
+	}
+
+	return options, nil
+}
+
+// ParseQuestionRandomOptions builds the options for QuestionService Random
+// method from query parameters such as "category=tv&difficulty=easy".
+//
+// If any value cannot be parsed, ParseQuestionRandomOptions returns an
+// *opentrivia.ValidationError.
+func ParseQuestionRandomOptions(v url.Values) (*QuestionRandomOptions, error) {
+	options := &QuestionRandomOptions{
+		Token: Token(v.Get("token")),
+	}
+
+	p := &optionValues{v: v}
+	p.category(&options.Category)
+	p.difficulty(&options.Difficulty)
+	p.questionType(&options.Type)
+
+	if err := p.err(); err != nil {
+		return nil, err
+	}
+
+	return options, nil
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface. It accepts the
+// numeric IDs sent by the Open Trivia API as well as any string accepted by
+// ParseQuestionCategory.
+func (c *QuestionCategory) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '"' {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+
+		return c.UnmarshalText([]byte(s))
+	}
+
+	var id uint8
+	if err := json.Unmarshal(data, &id); err != nil {
+		return err
+	}
+
+	*c = QuestionCategory(id)
+	return nil
+}
@@ -0,0 +1,371 @@
+// Package prefetch keeps questions ready before they are asked for, so the
+// first question of a quiz does not wait for the Open Trivia API.
+//
+// A Prefetcher keeps a pool of questions for each combination of category,
+// difficulty and type, refilled in the background with a session token, so
+// the questions of a pool are not repeated until the token is exhausted:
+//
+//	p := prefetch.New(client, 10)
+//	defer p.Close()
+//
+//	p.Warm(prefetch.Key{Category: opentrivia.QuestionCategoryHistory})
+//	q, err := p.Random(&opentrivia.QuestionRandomOptions{Category: opentrivia.QuestionCategoryHistory})
+package prefetch
+
+import (
+	"context"
+	"sync"
+
+	"github.com/pinheirolucas/opentrivia"
+	"github.com/pkg/errors"
+)
+
+// DefaultSize is the number of questions kept ready in each pool when no
+// size is provided.
+const DefaultSize = 10
+
+// maxLimit is the most questions fetched by a request.
+const maxLimit = 50
+
+// ErrClosed is returned by the methods of a closed Prefetcher.
+var ErrClosed = errors.New("prefetch: the prefetcher is closed")
+
+// Key identifies a pool. The zero values mean any category, difficulty or
+// type.
+type Key struct {
+	Category   opentrivia.QuestionCategory
+	Difficulty opentrivia.QuestionDifficulty
+	Type       opentrivia.QuestionType
+}
+
+// Level is the fill level of a pool.
+type Level struct {
+	// Ready is the number of questions in the pool.
+	Ready int
+
+	// Size is the number of questions the pool is refilled to.
+	Size int
+
+	// Refilling is true while questions are fetched for the pool.
+	Refilling bool
+}
+
+// pool holds the questions of a key.
+type pool struct {
+	questions []opentrivia.Question
+
+	// known holds the fingerprints of the questions.
+	known map[string]bool
+
+	token     opentrivia.Token
+	refilling bool
+	err       error
+
+	// changed is closed, and replaced, whenever the pool changes.
+	changed chan struct{}
+}
+
+func (pl *pool) notify() {
+	close(pl.changed)
+	pl.changed = make(chan struct{})
+}
+
+// Prefetcher keeps questions ready for each Key asked for. It is safe for
+// concurrent use.
+type Prefetcher struct {
+	client *opentrivia.Client
+	size   int
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	mu     sync.Mutex
+	pools  map[Key]*pool
+	closed bool
+}
+
+// New returns a Prefetcher keeping size questions ready in each pool, or
+// DefaultSize if size is not positive. The questions are fetched with the
+// client. The caller should call Close when finished, to stop the refills.
+func New(client *opentrivia.Client, size int) *Prefetcher {
+	if size <= 0 {
+		size = DefaultSize
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &Prefetcher{
+		client: client,
+		size:   size,
+		ctx:    ctx,
+		cancel: cancel,
+		pools:  make(map[Key]*pool),
+	}
+}
+
+// Warm starts to fill the pools of the keys, ahead of the first requests.
+func (p *Prefetcher) Warm(keys ...Key) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed {
+		return ErrClosed
+	}
+
+	for _, key := range keys {
+		p.refill(key, p.pool(key))
+	}
+
+	return nil
+}
+
+// Random returns a question matching the options from its pool, which is
+// refilled in the background. If the pool is empty, Random waits for the
+// refill. The token and the auto-refresh of the options are ignored, as the
+// pools manage their own tokens.
+func (p *Prefetcher) Random(options *opentrivia.QuestionRandomOptions) (opentrivia.Question, error) {
+	return p.RandomContext(context.Background(), options)
+}
+
+// RandomContext is like Random, but stops waiting for the refill once the
+// context is done.
+func (p *Prefetcher) RandomContext(ctx context.Context, options *opentrivia.QuestionRandomOptions) (opentrivia.Question, error) {
+	var key Key
+	if options != nil {
+		if err := options.Validate(); err != nil {
+			return opentrivia.Question{}, err
+		}
+
+		key = Key{Category: options.Category, Difficulty: options.Difficulty, Type: options.Type}
+	}
+
+	return p.Next(ctx, key)
+}
+
+// Next returns a question from the pool of the key, waiting for the refill
+// if the pool is empty.
+func (p *Prefetcher) Next(ctx context.Context, key Key) (opentrivia.Question, error) {
+	hit := true
+	started := false
+
+	for {
+		p.mu.Lock()
+		if p.closed {
+			p.mu.Unlock()
+			return opentrivia.Question{}, ErrClosed
+		}
+
+		pl := p.pool(key)
+
+		if len(pl.questions) > 0 {
+			q := pl.questions[0]
+			pl.questions = pl.questions[1:]
+			delete(pl.known, q.Fingerprint())
+			p.refill(key, pl)
+			p.mu.Unlock()
+
+			p.count(hit)
+			return q, nil
+		}
+		hit = false
+
+		if !pl.refilling {
+			// The error of a refill started by this call is returned. An
+			// older error is forgotten, and the refill is tried again.
+			if pl.err != nil && started {
+				err := pl.err
+				pl.err = nil
+				p.mu.Unlock()
+
+				p.count(hit)
+				return opentrivia.Question{}, err
+			}
+
+			pl.err = nil
+			p.refill(key, pl)
+			started = true
+		}
+
+		changed := pl.changed
+		p.mu.Unlock()
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return opentrivia.Question{}, ctx.Err()
+		case <-p.ctx.Done():
+			return opentrivia.Question{}, ErrClosed
+		}
+	}
+}
+
+// Levels returns the fill level of each pool.
+func (p *Prefetcher) Levels() map[Key]Level {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	levels := make(map[Key]Level, len(p.pools))
+	for key, pl := range p.pools {
+		levels[key] = Level{Ready: len(pl.questions), Size: p.size, Refilling: pl.refilling}
+	}
+
+	return levels
+}
+
+// Close stops the refills and waits for them to return. The requests
+// waiting for a question fail with ErrClosed.
+func (p *Prefetcher) Close() error {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return nil
+	}
+	p.closed = true
+	p.mu.Unlock()
+
+	p.cancel()
+	p.wg.Wait()
+
+	return nil
+}
+
+// pool returns the pool of the key, creating it if needed. It must be
+// called with p.mu held.
+func (p *Prefetcher) pool(key Key) *pool {
+	pl, ok := p.pools[key]
+	if !ok {
+		pl = &pool{known: make(map[string]bool), changed: make(chan struct{})}
+		p.pools[key] = pl
+	}
+
+	return pl
+}
+
+// refill starts to refill the pool, unless it is full or already being
+// refilled. It must be called with p.mu held.
+func (p *Prefetcher) refill(key Key, pl *pool) {
+	if pl.refilling || len(pl.questions) >= p.size {
+		return
+	}
+
+	pl.refilling = true
+	p.wg.Add(1)
+
+	go func() {
+		defer p.wg.Done()
+
+		err := p.fill(key, pl)
+
+		p.mu.Lock()
+		defer p.mu.Unlock()
+
+		pl.refilling = false
+		pl.err = err
+		pl.notify()
+	}()
+}
+
+// fill fetches questions until the pool is full, or until there are no
+// more questions for the key.
+func (p *Prefetcher) fill(key Key, pl *pool) error {
+	// limit is lowered when the Open Trivia API has less questions for the
+	// key than requested, as it has no results then.
+	limit := maxLimit
+	renewed := false
+
+	for {
+		p.mu.Lock()
+		need := p.size - len(pl.questions)
+		token := pl.token
+		p.mu.Unlock()
+
+		if need <= 0 {
+			return nil
+		}
+		if need > limit {
+			need = limit
+		}
+
+		if token == "" {
+			var err error
+			if token, err = p.client.Token.CreateContext(p.ctx); err != nil {
+				return err
+			}
+		}
+
+		options := &opentrivia.QuestionListOptions{
+			AutoRefresh:          true,
+			AllowUnknownCategory: true,
+			Category:             key.Category,
+			Difficulty:           key.Difficulty,
+			Limit:                uint8(need),
+			Token:                token,
+			Type:                 key.Type,
+		}
+
+		questions, err := p.client.Question.ListContext(p.ctx, options)
+		switch errors.Cause(err) {
+		case nil:
+		case opentrivia.ErrTokenNotFound:
+			// The token expired, after 6 hours without use on the Open
+			// Trivia API. A new one is created once.
+			if renewed {
+				return err
+			}
+			renewed = true
+
+			p.mu.Lock()
+			pl.token = ""
+			p.mu.Unlock()
+			continue
+		case opentrivia.ErrNoResults:
+			if need == 1 {
+				return err
+			}
+
+			limit = need / 2
+			continue
+		default:
+			return err
+		}
+
+		p.mu.Lock()
+		// The token changes when it is refreshed.
+		pl.token = options.Token
+
+		added := 0
+		for _, q := range questions {
+			// A refreshed token may return the questions still in the
+			// pool.
+			fingerprint := q.Fingerprint()
+			if pl.known[fingerprint] {
+				continue
+			}
+
+			pl.known[fingerprint] = true
+			pl.questions = append(pl.questions, q)
+			added++
+		}
+		pl.notify()
+		p.mu.Unlock()
+
+		if added == 0 {
+			return opentrivia.ErrNoResults
+		}
+	}
+}
+
+// count emits a cache hit or miss to the metrics of the client.
+func (p *Prefetcher) count(hit bool) {
+	if p.client.Metrics == nil {
+		return
+	}
+
+	name := opentrivia.MetricCacheMisses
+	if hit {
+		name = opentrivia.MetricCacheHits
+	}
+
+	p.client.Metrics.Add(name, 1, opentrivia.Labels{"cache": "prefetch"})
+}
@@ -1,6 +1,13 @@
 package opentrivia
 
 import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"html"
+	"io"
+	"strings"
+
 	"github.com/google/go-querystring/query"
 	shuffle "github.com/shogo82148/go-shuffle"
 )
@@ -131,6 +138,10 @@ type QuestionListOptions struct {
 	// If true, the request will refresh the provided token when needed.
 	AutoRefresh bool `url:"-"`
 
+	// If true, categories unknown to this client will not be reported by
+	// Validate and will be sent to the Open Trivia API.
+	AllowUnknownCategory bool `url:"-"`
+
 	// The maximum limit is 50.
 	Category   QuestionCategory   `url:"category,omitempty"`
 	Difficulty QuestionDifficulty `url:"difficulty,omitempty"`
@@ -145,6 +156,10 @@ type QuestionRandomOptions struct {
 	// If true, the request will refresh the provided token when needed.
 	AutoRefresh bool `url:"-"`
 
+	// If true, categories unknown to this client will not be reported by
+	// Validate and will be sent to the Open Trivia API.
+	AllowUnknownCategory bool `url:"-"`
+
 	Category   QuestionCategory   `url:"category,omitempty"`
 	Difficulty QuestionDifficulty `url:"difficulty,omitempty"`
 	Token      Token              `url:"token,omitempty"`
@@ -172,15 +187,53 @@ func (q *Question) IsAnswerCorrect(answer string) bool {
 	return answer == q.CorrectAnswer
 }
 
+// Fingerprint returns a stable identifier of the question. It ignores the
+// HTML entities, the case and the punctuation of the texts, so the same
+// question is recognized whenever it is fetched again.
+func (q *Question) Fingerprint() string {
+	h := sha1.New()
+	for _, s := range []string{q.Category, q.Type, q.Question, q.CorrectAnswer} {
+		io.WriteString(h, normalize(html.UnescapeString(s)))
+		h.Write([]byte{0})
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
 // ShuffleAnswers merging the correct answer with the incorrect answers
 func (q *Question) ShuffleAnswers() []string {
-	answers := append(q.IncorrectAnswers, q.CorrectAnswer)
+	// A new slice keeps the shuffle from reordering q.IncorrectAnswers when
+	// it has spare capacity.
+	answers := make([]string, 0, len(q.IncorrectAnswers)+1)
+	answers = append(answers, q.IncorrectAnswers...)
+	answers = append(answers, q.CorrectAnswer)
 
 	shuffle.Strings(answers)
 
 	return answers
 }
 
+// ChooseAnswer returns the answer picked by the input, either by the letter
+// of its position in answers, such as B, or by its text. The input is
+// returned as is if it picks no answer.
+func ChooseAnswer(answers []string, input string) string {
+	input = strings.TrimSpace(input)
+	if len(input) == 1 {
+		i := int(strings.ToUpper(input)[0] - 'A')
+		if i >= 0 && i < len(answers) {
+			return answers[i]
+		}
+	}
+
+	for _, a := range answers {
+		if strings.EqualFold(html.UnescapeString(a), input) {
+			return a
+		}
+	}
+
+	return input
+}
+
 // QuestionService handles communication with the question related
 // methods of the Open Trivia API.
 //
@@ -189,16 +242,35 @@ type QuestionService service
 
 // List returns a list of random questions from Open Trivia API.
 //
-// If options is nil, List will use opentrivia.DefaultQuestionListOptions.
+// If options is nil, List will use the options set by
+// WithDefaultListOptions, or opentrivia.DefaultQuestionListOptions.
+//
+// The options are validated before the request is sent. If they are not
+// valid, List will return an *opentrivia.ValidationError.
 func (q *QuestionService) List(options *QuestionListOptions) ([]Question, error) {
+	return q.ListContext(context.Background(), options)
+}
+
+// ListContext is like List, but sends the request with the context and
+// starts its span as a child of the span of the context.
+func (q *QuestionService) ListContext(ctx context.Context, options *QuestionListOptions) (questions []Question, err error) {
+	ctx, span := q.client.tracer().Start(ctx, SpanQuestionList)
+	defer func() { endSpan(span, err) }()
+
 	if options == nil {
-		options = DefaultQuestionListOptions
+		options = q.client.defaultListOptions()
 	} else if options.Limit <= 0 {
-		options.Limit = DefaultQuestionListOptions.Limit
+		options.Limit = q.client.defaultListOptions().Limit
 	} else if options.Limit > 50 {
 		options.Limit = 50
 	}
 
+	setOptionAttributes(span, options.Category, options.Difficulty, options.Type, options.Limit)
+
+	if err := options.Validate(); err != nil {
+		return []Question{}, err
+	}
+
 	v, err := query.Values(options)
 	if err != nil {
 		return []Question{}, err
@@ -210,40 +282,78 @@ func (q *QuestionService) List(options *QuestionListOptions) ([]Question, error)
 	}
 
 	var resp questionResponse
-	if _, err := q.client.Do(req, &resp); err != nil {
+	if _, err := q.client.Do(req.WithContext(ctx), &resp); err != nil {
 		return []Question{}, err
 	}
 
+	span.SetAttribute("opentrivia.response_code", int(resp.ResponseCode))
+
 	switch resp.ResponseCode {
-	case responseCodeInvalidParameter:
-		return []Question{}, ErrInvalidParameter
-	case responseCodeNoResults:
-		return []Question{}, ErrNoResults
+	case responseCodeSuccess:
 	case responseCodeTokenEmpty:
 		if options.AutoRefresh {
-			t, err := q.client.Token.Refresh(options.Token)
+			t, err := q.client.Token.refresh(ctx, options.Token, true)
 			if err != nil {
 				return []Question{}, err
 			}
 
+			q.client.metrics().Add(MetricRetries, 1, Labels{"endpoint": defaultAPIRoute, "reason": "token_empty"})
+
 			options.Token = t
-			return q.client.Question.List(options)
+			return q.retryList(ctx, options)
 		}
 
 		return []Question{}, ErrTokenEmpty
-	case responseCodeTokenNotFound:
-		return []Question{}, ErrTokenNotFound
+	default:
+		return []Question{}, resp.ResponseCode.err()
+	}
+
+	if resp.Results == nil {
+		return []Question{}, nil
 	}
 
 	return resp.Results, nil
 }
 
+// retryList sends the list request again within a retry span.
+func (q *QuestionService) retryList(ctx context.Context, options *QuestionListOptions) (questions []Question, err error) {
+	ctx, span := q.client.tracer().Start(ctx, SpanRetry)
+	span.SetAttribute("opentrivia.reason", "token_empty")
+	defer func() { endSpan(span, err) }()
+
+	return q.ListContext(ctx, options)
+}
+
 // Random returns a random question from Open Trivia API.
 //
-// If options is nil, Random will use opentrivia.DefaultQuestionRandomOptions.
+// If options is nil, Random will use the options set by
+// WithDefaultRandomOptions, or opentrivia.DefaultQuestionRandomOptions.
+//
+// The options are validated before the request is sent. If they are not
+// valid, Random will return an *opentrivia.ValidationError.
+//
+// If the Open Trivia API answers without a question, Random will return
+// opentrivia.ErrNoResults. A rate limited request returns
+// opentrivia.ErrRateLimit, and an unknown response code an
+// *opentrivia.UnknownResponseCodeError.
 func (q *QuestionService) Random(options *QuestionRandomOptions) (Question, error) {
+	return q.RandomContext(context.Background(), options)
+}
+
+// RandomContext is like Random, but sends the request with the context and
+// starts its span as a child of the span of the context.
+func (q *QuestionService) RandomContext(ctx context.Context, options *QuestionRandomOptions) (question Question, err error) {
+	ctx, span := q.client.tracer().Start(ctx, SpanQuestionRandom)
+	defer func() { endSpan(span, err) }()
+
 	if options == nil {
-		options = DefaultQuestionRandomOptions
+		options = q.client.defaultRandomOptions()
+	}
+
+	setOptionAttributes(span, options.Category, options.Difficulty, options.Type, 1)
+
+	if err := options.Validate(); err != nil {
+		return Question{}, err
 	}
 
 	v, err := query.Values(options)
@@ -259,30 +369,46 @@ func (q *QuestionService) Random(options *QuestionRandomOptions) (Question, erro
 	}
 
 	var resp questionResponse
-	if _, err := q.client.Do(req, &resp); err != nil {
+	if _, err := q.client.Do(req.WithContext(ctx), &resp); err != nil {
 		return Question{}, err
 	}
 
+	span.SetAttribute("opentrivia.response_code", int(resp.ResponseCode))
+
 	switch resp.ResponseCode {
-	case responseCodeInvalidParameter:
-		return Question{}, ErrInvalidParameter
-	case responseCodeNoResults:
-		return Question{}, ErrNoResults
+	case responseCodeSuccess:
 	case responseCodeTokenEmpty:
 		if options.AutoRefresh {
-			t, err := q.client.Token.Refresh(options.Token)
+			t, err := q.client.Token.refresh(ctx, options.Token, true)
 			if err != nil {
 				return Question{}, err
 			}
 
+			q.client.metrics().Add(MetricRetries, 1, Labels{"endpoint": defaultAPIRoute, "reason": "token_empty"})
+
 			options.Token = t
-			return q.client.Question.Random(options)
+			return q.retryRandom(ctx, options)
 		}
 
 		return Question{}, ErrTokenEmpty
-	case responseCodeTokenNotFound:
-		return Question{}, ErrTokenNotFound
+	default:
+		return Question{}, resp.ResponseCode.err()
+	}
+
+	// A successful response should hold a question, but a misbehaving
+	// mirror may send none.
+	if len(resp.Results) == 0 {
+		return Question{}, ErrNoResults
 	}
 
 	return resp.Results[0], nil
 }
+
+// retryRandom sends the random request again within a retry span.
+func (q *QuestionService) retryRandom(ctx context.Context, options *QuestionRandomOptions) (question Question, err error) {
+	ctx, span := q.client.tracer().Start(ctx, SpanRetry)
+	span.SetAttribute("opentrivia.reason", "token_empty")
+	defer func() { endSpan(span, err) }()
+
+	return q.RandomContext(ctx, options)
+}
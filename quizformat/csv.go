@@ -0,0 +1,130 @@
+package quizformat
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/pinheirolucas/opentrivia"
+	"github.com/pkg/errors"
+)
+
+const incorrectAnswerColumn = "incorrect_answer_"
+
+// EncodeCSV writes the questions as CSV, with a header and one row per
+// question. The columns are category, type, difficulty, question,
+// correct_answer and one incorrect_answer_N column for each incorrect
+// answer.
+func EncodeCSV(w io.Writer, questions []opentrivia.Question) error {
+	incorrect := 0
+	for _, q := range questions {
+		if len(q.IncorrectAnswers) > incorrect {
+			incorrect = len(q.IncorrectAnswers)
+		}
+	}
+
+	header := []string{"category", "type", "difficulty", "question", "correct_answer"}
+	for i := 1; i <= incorrect; i++ {
+		header = append(header, incorrectAnswerColumn+strconv.Itoa(i))
+	}
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+
+	for _, q := range questions {
+		row := make([]string, len(header))
+		row[0] = q.Category
+		row[1] = questionType(q)
+		row[2] = q.Difficulty
+		row[3] = q.Question
+		row[4] = q.CorrectAnswer
+		copy(row[5:], q.IncorrectAnswers)
+
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// DecodeCSV reads questions written by EncodeCSV. The columns are matched by
+// the header, so they may be reordered, and empty incorrect answers are
+// ignored.
+func DecodeCSV(r io.Reader) ([]opentrivia.Question, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+
+	header, err := cr.Read()
+	if err == io.EOF {
+		return []opentrivia.Question{}, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "quizformat: error reading CSV header")
+	}
+
+	columns := make(map[string]int)
+	var incorrect []int
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if strings.HasPrefix(name, incorrectAnswerColumn) {
+			incorrect = append(incorrect, i)
+			continue
+		}
+
+		columns[name] = i
+	}
+
+	for _, name := range []string{"question", "correct_answer"} {
+		if _, ok := columns[name]; !ok {
+			return nil, errors.Errorf("quizformat: missing CSV column %q", name)
+		}
+	}
+
+	questions := []opentrivia.Question{}
+	for line := 2; ; line++ {
+		row, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "quizformat: error reading CSV")
+		}
+
+		field := func(name string) string {
+			i, ok := columns[name]
+			if !ok || i >= len(row) {
+				return ""
+			}
+
+			return row[i]
+		}
+
+		q := opentrivia.Question{
+			Category:         field("category"),
+			Type:             field("type"),
+			Difficulty:       field("difficulty"),
+			Question:         field("question"),
+			CorrectAnswer:    field("correct_answer"),
+			IncorrectAnswers: []string{},
+		}
+		for _, i := range incorrect {
+			if i < len(row) && row[i] != "" {
+				q.IncorrectAnswers = append(q.IncorrectAnswers, row[i])
+			}
+		}
+
+		if q.Question == "" || q.CorrectAnswer == "" {
+			return nil, errors.Errorf("quizformat: line %d: missing question or correct answer", line)
+		}
+
+		q.Type = questionType(q)
+		questions = append(questions, q)
+	}
+
+	return questions, nil
+}
@@ -0,0 +1,244 @@
+package quizformat
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/pinheirolucas/opentrivia"
+	"github.com/pkg/errors"
+)
+
+const (
+	giftCategoryPrefix   = "$CATEGORY:"
+	giftDifficultyPrefix = "// difficulty:"
+	giftSpecials         = `~=#{}:\`
+)
+
+var giftEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	"~", `\~`,
+	"=", `\=`,
+	"#", `\#`,
+	"{", `\{`,
+	"}", `\}`,
+	":", `\:`,
+)
+
+// EncodeGIFT writes the questions in the GIFT format of Moodle.
+//
+// The categories are written as $CATEGORY directives and the difficulties,
+// which GIFT does not support, as comments read back by DecodeGIFT.
+func EncodeGIFT(w io.Writer, questions []opentrivia.Question) error {
+	bw := bufio.NewWriter(w)
+
+	category := ""
+	for i, q := range questions {
+		if q.Category != category {
+			category = q.Category
+			fmt.Fprintf(bw, "%s %s\n\n", giftCategoryPrefix, category)
+		}
+
+		if q.Difficulty != "" {
+			fmt.Fprintf(bw, "%s %s\n", giftDifficultyPrefix, q.Difficulty)
+		}
+		fmt.Fprintf(bw, "::Q%d::%s", i+1, giftEscaper.Replace(q.Question))
+
+		if questionType(q) == string(opentrivia.QuestionTypeTrueFalse) && isTrueFalse(q.CorrectAnswer, q.IncorrectAnswers) {
+			fmt.Fprintf(bw, "{%s}\n\n", strings.ToUpper(q.CorrectAnswer))
+			continue
+		}
+
+		fmt.Fprint(bw, "{\n")
+		fmt.Fprintf(bw, "\t=%s\n", giftEscaper.Replace(q.CorrectAnswer))
+		for _, a := range q.IncorrectAnswers {
+			fmt.Fprintf(bw, "\t~%s\n", giftEscaper.Replace(a))
+		}
+		fmt.Fprint(bw, "}\n\n")
+	}
+
+	return bw.Flush()
+}
+
+// DecodeGIFT reads multiple choice and true/false questions in the GIFT
+// format of Moodle. The feedbacks and the weights of the answers are
+// ignored.
+func DecodeGIFT(r io.Reader) ([]opentrivia.Question, error) {
+	questions := []opentrivia.Question{}
+
+	var (
+		category, difficulty string
+		block                []string
+		line                 int
+	)
+
+	flush := func() error {
+		defer func() { block = nil }()
+
+		text := strings.TrimSpace(strings.Join(block, "\n"))
+		if text == "" {
+			return nil
+		}
+
+		q, err := parseGIFTQuestion(text)
+		if err != nil {
+			return errors.Wrapf(err, "quizformat: line %d", line)
+		}
+
+		q.Category = category
+		q.Difficulty = difficulty
+		questions = append(questions, q)
+		difficulty = ""
+
+		return nil
+	}
+
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		line++
+		text := strings.TrimSpace(s.Text())
+
+		switch {
+		case text == "":
+			if err := flush(); err != nil {
+				return nil, err
+			}
+		case strings.HasPrefix(text, giftCategoryPrefix):
+			category = strings.TrimSpace(strings.TrimPrefix(text, giftCategoryPrefix))
+		case strings.HasPrefix(text, giftDifficultyPrefix):
+			difficulty = strings.TrimSpace(strings.TrimPrefix(text, giftDifficultyPrefix))
+		case strings.HasPrefix(text, "//"):
+			// Comments are ignored.
+		default:
+			block = append(block, text)
+		}
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := flush(); err != nil {
+		return nil, err
+	}
+
+	return questions, nil
+}
+
+// parseGIFTQuestion parses a question such as "::title::text{=a ~b ~c}".
+func parseGIFTQuestion(text string) (opentrivia.Question, error) {
+	q := opentrivia.Question{IncorrectAnswers: []string{}}
+
+	if strings.HasPrefix(text, "::") {
+		end := indexUnescaped(text[2:], "::")
+		if end < 0 {
+			return q, errors.New("unterminated question title")
+		}
+
+		text = text[end+4:]
+	}
+
+	open := indexUnescaped(text, "{")
+	if open < 0 {
+		return q, errors.New("missing answers")
+	}
+	end := indexUnescaped(text[open:], "}")
+	if end < 0 {
+		return q, errors.New("unterminated answers")
+	}
+
+	q.Question = unescapeGIFT(strings.TrimSpace(text[:open] + " " + text[open+end+1:]))
+	answers := strings.TrimSpace(text[open+1 : open+end])
+
+	switch strings.ToUpper(answers) {
+	case "T", "TRUE":
+		q.Type = string(opentrivia.QuestionTypeTrueFalse)
+		q.CorrectAnswer = "True"
+		q.IncorrectAnswers = []string{"False"}
+		return q, nil
+	case "F", "FALSE":
+		q.Type = string(opentrivia.QuestionTypeTrueFalse)
+		q.CorrectAnswer = "False"
+		q.IncorrectAnswers = []string{"True"}
+		return q, nil
+	}
+
+	for _, a := range splitGIFTAnswers(answers) {
+		value := a[1:]
+		if i := indexUnescaped(value, "#"); i >= 0 {
+			value = value[:i]
+		}
+		if strings.HasPrefix(value, "%") {
+			if i := strings.Index(value[1:], "%"); i >= 0 {
+				value = value[i+2:]
+			}
+		}
+		value = unescapeGIFT(strings.TrimSpace(value))
+
+		if a[0] == '=' {
+			q.CorrectAnswer = value
+		} else {
+			q.IncorrectAnswers = append(q.IncorrectAnswers, value)
+		}
+	}
+
+	if q.CorrectAnswer == "" {
+		return q, errors.New("missing correct answer")
+	}
+
+	q.Type = questionType(q)
+	return q, nil
+}
+
+// splitGIFTAnswers splits the answers at each unescaped = or ~, keeping the
+// marker as the first byte of each answer.
+func splitGIFTAnswers(s string) []string {
+	var (
+		answers []string
+		start   = -1
+	)
+
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '=', '~':
+			if start >= 0 {
+				answers = append(answers, s[start:i])
+			}
+			start = i
+		}
+	}
+	if start >= 0 {
+		answers = append(answers, s[start:])
+	}
+
+	return answers
+}
+
+// indexUnescaped returns the index of the first unescaped sep in s, or -1.
+func indexUnescaped(s, sep string) int {
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' {
+			i++
+			continue
+		}
+		if strings.HasPrefix(s[i:], sep) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func unescapeGIFT(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) && strings.IndexByte(giftSpecials, s[i+1]) >= 0 {
+			i++
+		}
+		b.WriteByte(s[i])
+	}
+
+	return b.String()
+}
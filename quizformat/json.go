@@ -0,0 +1,55 @@
+package quizformat
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+
+	"github.com/pinheirolucas/opentrivia"
+	"github.com/pkg/errors"
+)
+
+// EncodeJSON writes the questions as an indented JSON array, with the same
+// fields as the results of the Open Trivia API.
+func EncodeJSON(w io.Writer, questions []opentrivia.Question) error {
+	if questions == nil {
+		questions = []opentrivia.Question{}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(questions)
+}
+
+// DecodeJSON reads a JSON array of questions. A response of the Open Trivia
+// API, with the questions under results, is accepted as well.
+func DecodeJSON(r io.Reader) ([]opentrivia.Question, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	questions := []opentrivia.Question{}
+	if data = bytes.TrimSpace(data); len(data) > 0 && data[0] == '{' {
+		var resp struct {
+			Results []opentrivia.Question `json:"results"`
+		}
+		err = json.Unmarshal(data, &resp)
+		if resp.Results != nil {
+			questions = resp.Results
+		}
+	} else if len(data) > 0 {
+		err = json.Unmarshal(data, &questions)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "quizformat: error decoding JSON")
+	}
+
+	for i := range questions {
+		questions[i].Type = questionType(questions[i])
+	}
+
+	return questions, nil
+}
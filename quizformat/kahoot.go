@@ -0,0 +1,127 @@
+package quizformat
+
+import (
+	"encoding/csv"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pinheirolucas/opentrivia"
+	"github.com/pkg/errors"
+)
+
+// KahootTimeLimit is the time limit, in seconds, written by EncodeKahoot.
+const KahootTimeLimit = 20
+
+// kahootAnswers is the number of answer columns of a Kahoot spreadsheet.
+const kahootAnswers = 4
+
+var kahootHeader = []string{
+	"Question - max 120 characters",
+	"Answer 1 - max 75 characters",
+	"Answer 2 - max 75 characters",
+	"Answer 3 - max 75 characters",
+	"Answer 4 - max 75 characters",
+	"Time limit (sec) - 5, 10, 20, 30, 60, 90, 120, or 240 secs",
+	"Correct answer(s) - choose at least one",
+	"Category",
+	"Difficulty",
+	"Type",
+}
+
+// EncodeKahoot writes the questions as a CSV export of the Kahoot quiz
+// spreadsheet. The answers are sorted, so the correct one is not always on
+// the same column, and the category, the difficulty and the type are kept on
+// extra columns ignored by Kahoot.
+//
+// Kahoot supports up to four answers, so questions with more than three
+// incorrect answers are rejected.
+func EncodeKahoot(w io.Writer, questions []opentrivia.Question) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(kahootHeader); err != nil {
+		return err
+	}
+
+	for i, q := range questions {
+		if len(q.IncorrectAnswers)+1 > kahootAnswers {
+			return errors.Errorf("quizformat: question %d has more than %d answers", i+1, kahootAnswers)
+		}
+
+		answers := append([]string{q.CorrectAnswer}, q.IncorrectAnswers...)
+		if questionType(q) == string(opentrivia.QuestionTypeTrueFalse) {
+			sort.Sort(sort.Reverse(sort.StringSlice(answers)))
+		} else {
+			sort.Strings(answers)
+		}
+
+		row := make([]string, len(kahootHeader))
+		row[0] = q.Question
+		for j, a := range answers {
+			row[1+j] = a
+			if a == q.CorrectAnswer {
+				row[6] = strconv.Itoa(j + 1)
+			}
+		}
+		row[5] = strconv.Itoa(KahootTimeLimit)
+		row[7] = q.Category
+		row[8] = q.Difficulty
+		row[9] = questionType(q)
+
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// DecodeKahoot reads questions from a CSV export of a Kahoot quiz
+// spreadsheet. Only the first correct answer of each question is kept.
+func DecodeKahoot(r io.Reader) ([]opentrivia.Question, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+
+	rows, err := cr.ReadAll()
+	if err != nil {
+		return nil, errors.Wrap(err, "quizformat: error reading Kahoot spreadsheet")
+	}
+
+	questions := []opentrivia.Question{}
+	for i, row := range rows {
+		// The header and the instructions of the template are skipped.
+		if i == 0 || len(row) < 7 || row[0] == "" {
+			continue
+		}
+		if _, err := strconv.Atoi(strings.TrimSpace(row[5])); err != nil {
+			continue
+		}
+
+		correct, err := strconv.Atoi(strings.SplitN(strings.TrimSpace(row[6]), ",", 2)[0])
+		if err != nil || correct < 1 || correct > kahootAnswers || row[correct] == "" {
+			return nil, errors.Errorf("quizformat: row %d: invalid correct answer %q", i+1, row[6])
+		}
+
+		q := opentrivia.Question{
+			Question:         row[0],
+			CorrectAnswer:    row[correct],
+			IncorrectAnswers: []string{},
+		}
+		for j := 1; j <= kahootAnswers; j++ {
+			if j != correct && row[j] != "" {
+				q.IncorrectAnswers = append(q.IncorrectAnswers, row[j])
+			}
+		}
+		if len(row) > 9 {
+			q.Category = row[7]
+			q.Difficulty = row[8]
+			q.Type = row[9]
+		}
+
+		q.Type = questionType(q)
+		questions = append(questions, q)
+	}
+
+	return questions, nil
+}
@@ -0,0 +1,82 @@
+// Package quizformat converts questions to and from the formats used to
+// exchange quizzes: CSV, GIFT (Moodle), Kahoot spreadsheets and JSON.
+//
+// Every format preserves the category, the difficulty, the type and the
+// split between the correct and the incorrect answers, so questions listed
+// from the Open Trivia API can be exported, edited and imported back:
+//
+//	questions, err := client.Question.List(options)
+//	err = quizformat.EncodeGIFT(w, questions)
+//
+// The text of the questions is kept as is. The Open Trivia API sends it
+// HTML encoded by default.
+package quizformat
+
+import (
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/pinheirolucas/opentrivia"
+	"github.com/pkg/errors"
+)
+
+// Format is a named pair of encoder and decoder.
+type Format struct {
+	Name   string
+	Encode func(w io.Writer, questions []opentrivia.Question) error
+	Decode func(r io.Reader) ([]opentrivia.Question, error)
+}
+
+var formats = map[string]Format{
+	"csv":    {Name: "csv", Encode: EncodeCSV, Decode: DecodeCSV},
+	"gift":   {Name: "gift", Encode: EncodeGIFT, Decode: DecodeGIFT},
+	"kahoot": {Name: "kahoot", Encode: EncodeKahoot, Decode: DecodeKahoot},
+	"json":   {Name: "json", Encode: EncodeJSON, Decode: DecodeJSON},
+}
+
+// Lookup returns the format with the provided name: csv, gift, kahoot or
+// json.
+func Lookup(name string) (Format, error) {
+	f, ok := formats[strings.ToLower(name)]
+	if !ok {
+		return Format{}, errors.Errorf("quizformat: unknown format %q", name)
+	}
+
+	return f, nil
+}
+
+// Names returns the names of the available formats, sorted.
+func Names() []string {
+	names := make([]string, 0, len(formats))
+	for name := range formats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// isTrueFalse reports whether the answers are the ones of a true/false
+// question.
+func isTrueFalse(correct string, incorrect []string) bool {
+	if len(incorrect) != 1 {
+		return false
+	}
+
+	a, b := strings.ToLower(correct), strings.ToLower(incorrect[0])
+	return (a == "true" && b == "false") || (a == "false" && b == "true")
+}
+
+// questionType returns the type of the question, inferring it from the
+// answers when it is empty.
+func questionType(q opentrivia.Question) string {
+	if q.Type != "" {
+		return q.Type
+	}
+	if isTrueFalse(q.CorrectAnswer, q.IncorrectAnswers) {
+		return string(opentrivia.QuestionTypeTrueFalse)
+	}
+
+	return string(opentrivia.QuestionTypeMultiple)
+}
@@ -0,0 +1,131 @@
+package opentrivia
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+)
+
+// DefaultMaxResponseSize is the largest response body read by a Client,
+// unless it was created WithMaxResponseSize. The largest responses of the
+// Open Trivia API, 50 questions, are far below it.
+const DefaultMaxResponseSize = 1 << 20
+
+// maxSnippet is the most bytes of the body kept by a ResponseError.
+const maxSnippet = 512
+
+// ResponseError is returned when a response of the Open Trivia API can not
+// be decoded: its status is not 200 OK, its content type is not JSON, its
+// body is too large or it is not valid JSON. This typically happens when
+// BaseURL points at a misbehaving mirror or proxy.
+type ResponseError struct {
+	Method string
+
+	// URL is the URL of the request, with its token redacted.
+	URL string
+
+	StatusCode  int
+	ContentType string
+
+	// Body is the beginning of the body, truncated to a few hundred bytes.
+	Body string
+
+	// Reason describes what is wrong with the response.
+	Reason string
+
+	// Err is the error of the JSON decoder, if any.
+	Err error
+}
+
+func (e *ResponseError) Error() string {
+	s := fmt.Sprintf("opentrivia: invalid response from %s %s: %s", e.Method, e.URL, e.Reason)
+	if e.Err != nil {
+		s += ": " + e.Err.Error()
+	}
+	if e.Body != "" {
+		s += fmt.Sprintf(" (body: %q)", e.Body)
+	}
+
+	return s
+}
+
+// Unwrap returns the error of the JSON decoder, if any.
+func (e *ResponseError) Unwrap() error {
+	return e.Err
+}
+
+// decodeResponse checks the status and the content type of the response,
+// then decodes its body, up to max bytes, into v.
+func decodeResponse(req *http.Request, resp *http.Response, v interface{}, max int64) error {
+	newError := func(reason string, body []byte, err error) *ResponseError {
+		return &ResponseError{
+			Method:      req.Method,
+			URL:         redactURL(req.URL),
+			StatusCode:  resp.StatusCode,
+			ContentType: resp.Header.Get("Content-Type"),
+			Body:        snippet(body),
+			Reason:      reason,
+			Err:         err,
+		}
+	}
+
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, max+1))
+	if err != nil {
+		return newError("error reading the body", body, err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return newError(fmt.Sprintf("unexpected status %d %s", resp.StatusCode, http.StatusText(resp.StatusCode)), body, nil)
+	}
+	if !isJSON(resp.Header.Get("Content-Type")) {
+		return newError(fmt.Sprintf("unexpected content type %q", resp.Header.Get("Content-Type")), body, nil)
+	}
+	if int64(len(body)) > max {
+		return newError(fmt.Sprintf("the body exceeds %d bytes", max), body, nil)
+	}
+
+	if v == nil {
+		return nil
+	}
+
+	// Unlike a json.Decoder, Unmarshal rejects the data after the value.
+	if err := json.Unmarshal(body, v); err != nil {
+		return newError("error decoding the body", body, err)
+	}
+
+	return nil
+}
+
+// isJSON reports if the content type is JSON. A missing content type is
+// accepted, as some mirrors do not send it.
+func isJSON(contentType string) bool {
+	if contentType == "" {
+		return true
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+
+	return mediaType == "application/json" || mediaType == "text/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// snippet returns the beginning of the body, cut at a rune boundary.
+func snippet(body []byte) string {
+	if len(body) <= maxSnippet {
+		return string(body)
+	}
+
+	cut := maxSnippet
+	for cut > 0 && !utf8.RuneStart(body[cut]) {
+		cut--
+	}
+
+	return string(body[:cut]) + "..."
+}
@@ -0,0 +1,532 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"math/rand"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/pinheirolucas/opentrivia"
+	"github.com/pkg/errors"
+)
+
+// DefaultUpstreamInterval is the minimum interval between two requests to
+// the upstream Open Trivia API, which allows a single request every five
+// seconds per IP.
+const DefaultUpstreamInterval = 5 * time.Second
+
+// DefaultPoolTTL is the time a pool of questions is cached without being
+// used.
+const DefaultPoolTTL = 24 * time.Hour
+
+// maxUpstreamAmount is the maximum amount of questions the Open Trivia API
+// returns on a single request.
+const maxUpstreamAmount = 50
+
+// sweepInterval is the minimum interval between two deletions of the
+// expired entries, which are made while serving the requests.
+const sweepInterval = time.Minute
+
+// limiter spaces the calls to wait by a minimum interval.
+type limiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+// wait blocks until the next slot or until ctx is done. A slot is released
+// if ctx is done before it and no later slot was reserved.
+func (l *limiter) wait(ctx context.Context) error {
+	l.mu.Lock()
+	now := time.Now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	slot := l.next
+	l.next = slot.Add(l.interval)
+	l.mu.Unlock()
+
+	d := slot.Sub(now)
+	if d <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		l.mu.Lock()
+		if l.next.Equal(slot.Add(l.interval)) {
+			l.next = slot
+		}
+		l.mu.Unlock()
+
+		return ctx.Err()
+	}
+}
+
+type poolKey struct {
+	category   opentrivia.QuestionCategory
+	difficulty opentrivia.QuestionDifficulty
+	kind       opentrivia.QuestionType
+}
+
+// pool holds the questions already fetched from upstream for a query.
+type pool struct {
+	questions []opentrivia.Question
+	known     map[string]bool
+	lastUsed  time.Time
+}
+
+func (p *pool) add(questions []opentrivia.Question) {
+	for _, q := range questions {
+		if !p.known[q.Question] {
+			p.known[q.Question] = true
+			p.questions = append(p.questions, q)
+		}
+	}
+}
+
+// session is the state of a downstream token.
+type session struct {
+	// fetching serializes the requests of the session that reach
+	// upstream, so a single upstream token is created for it.
+	fetching sync.Mutex
+
+	// upstream is created lazily, when the pools run out of questions
+	// unseen by the session.
+	upstream opentrivia.Token
+	seen     map[string]bool
+	lastUsed time.Time
+
+	// resets counts the resets of the session, so a fetch that started
+	// before a reset does not restore the previous upstream token.
+	resets int
+}
+
+// Proxy is an http.Handler that fronts the Open Trivia API with the same
+// api.php and api_token.php contract.
+//
+// The downstream clients share a rate limiter, so together they never
+// exceed the upstream rate limit, and a cache of question pools, so most
+// requests do not reach upstream at all. The downstream tokens are issued by
+// the proxy, which manages an upstream token for each one of them.
+//
+// The tokens and the pools that are not used expire, and are deleted while
+// the proxy serves the requests.
+//
+// The fields of a Proxy must not be changed after it starts serving.
+type Proxy struct {
+	// TokenTTL is the time a token lives without being used. If zero,
+	// DefaultTokenTTL is used.
+	TokenTTL time.Duration
+
+	// PoolTTL is the time a pool of questions is cached without being
+	// used. If zero, DefaultPoolTTL is used.
+	PoolTTL time.Duration
+
+	// Now returns the current time. If nil, time.Now is used.
+	Now func() time.Time
+
+	upstream *opentrivia.Client
+	limiter  *limiter
+
+	mu         sync.Mutex
+	rand       *rand.Rand
+	pools      map[poolKey]*pool
+	sessions   map[opentrivia.Token]*session
+	categories []opentrivia.Category
+	lastSweep  time.Time
+}
+
+// NewProxy returns a Proxy to the upstream client. The requests to upstream
+// are spaced by at least interval.
+func NewProxy(upstream *opentrivia.Client, interval time.Duration) *Proxy {
+	return &Proxy{
+		upstream: upstream,
+		limiter:  &limiter{interval: interval},
+		rand:     rand.New(rand.NewSource(time.Now().UnixNano())),
+		pools:    make(map[poolKey]*pool),
+		sessions: make(map[opentrivia.Token]*session),
+	}
+}
+
+// ServeHTTP implements the http.Handler interface.
+func (p *Proxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	p.mu.Lock()
+	p.sweep(p.now())
+	p.mu.Unlock()
+
+	switch r.URL.Path {
+	case "/api.php":
+		p.serveQuestions(w, r)
+	case "/api_token.php":
+		p.serveToken(w, r)
+	case "/api_category.php":
+		p.serveCategories(w, r)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// Size returns the number of tokens and of question pools held by the
+// proxy.
+func (p *Proxy) Size() (tokens, pools int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return len(p.sessions), len(p.pools)
+}
+
+func (p *Proxy) now() time.Time {
+	if p.Now != nil {
+		return p.Now()
+	}
+
+	return time.Now()
+}
+
+// sweep deletes the expired tokens and pools, unless they were deleted less
+// than sweepInterval ago. It must be called with p.mu held.
+func (p *Proxy) sweep(now time.Time) {
+	if now.Sub(p.lastSweep) < sweepInterval {
+		return
+	}
+	p.lastSweep = now
+
+	tokenTTL := p.TokenTTL
+	if tokenTTL == 0 {
+		tokenTTL = DefaultTokenTTL
+	}
+	for token, s := range p.sessions {
+		if now.Sub(s.lastUsed) > tokenTTL {
+			delete(p.sessions, token)
+		}
+	}
+
+	poolTTL := p.PoolTTL
+	if poolTTL == 0 {
+		poolTTL = DefaultPoolTTL
+	}
+	for key, pl := range p.pools {
+		if now.Sub(pl.lastUsed) > poolTTL {
+			delete(p.pools, key)
+		}
+	}
+}
+
+// session returns the session of the token if it exists and has not
+// expired. It must be called with p.mu held.
+func (p *Proxy) session(token opentrivia.Token, now time.Time) (*session, bool) {
+	ttl := p.TokenTTL
+	if ttl == 0 {
+		ttl = DefaultTokenTTL
+	}
+
+	s, ok := p.sessions[token]
+	if !ok {
+		return nil, false
+	}
+	if now.Sub(s.lastUsed) > ttl {
+		delete(p.sessions, token)
+		return nil, false
+	}
+
+	s.lastUsed = now
+	return s, true
+}
+
+func (p *Proxy) serveQuestions(w http.ResponseWriter, r *http.Request) {
+	// The questions are cached as they are returned by upstream, HTML
+	// encoded, and encoded again if another encoding is requested.
+	encode := r.URL.Query().Get("encode")
+	switch encode {
+	case EncodingDefault, EncodingURLLegacy, EncodingURL3986, EncodingBase64:
+	default:
+		writeQuestions(w, ResponseCodeInvalidParameter, nil)
+		return
+	}
+
+	options, err := opentrivia.ParseQuestionListOptions(r.URL.Query())
+	if err == nil {
+		options.AllowUnknownCategory = true
+		err = options.Validate()
+	}
+	if err != nil {
+		writeQuestions(w, ResponseCodeInvalidParameter, nil)
+		return
+	}
+
+	questions, err := p.questions(r.Context(), options)
+	code, ok := responseCode(err)
+	if !ok {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	if encode != EncodingDefault {
+		for i, q := range questions {
+			questions[i] = encodeQuestion(unescapeQuestion(q), encode)
+		}
+	}
+
+	writeQuestions(w, code, questions)
+}
+
+// unescapeQuestion decodes the HTML entities of the text fields of the
+// question.
+func unescapeQuestion(q opentrivia.Question) opentrivia.Question {
+	incorrect := make([]string, len(q.IncorrectAnswers))
+	for i, a := range q.IncorrectAnswers {
+		incorrect[i] = html.UnescapeString(a)
+	}
+
+	return opentrivia.Question{
+		Category:         html.UnescapeString(q.Category),
+		Type:             html.UnescapeString(q.Type),
+		Difficulty:       html.UnescapeString(q.Difficulty),
+		Question:         html.UnescapeString(q.Question),
+		CorrectAnswer:    html.UnescapeString(q.CorrectAnswer),
+		IncorrectAnswers: incorrect,
+	}
+}
+
+// questions returns the questions for the options, fetching them from
+// upstream when the pool has not enough of them.
+func (p *Proxy) questions(ctx context.Context, options *opentrivia.QuestionListOptions) ([]opentrivia.Question, error) {
+	key := poolKey{
+		category:   options.Category,
+		difficulty: options.Difficulty,
+		kind:       options.Type,
+	}
+	amount := int(options.Limit)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := p.now()
+
+	var s *session
+	if options.Token != "" {
+		var ok bool
+		if s, ok = p.session(options.Token, now); !ok {
+			return nil, opentrivia.ErrTokenNotFound
+		}
+
+		p.mu.Unlock()
+		s.fetching.Lock()
+		defer s.fetching.Unlock()
+		p.mu.Lock()
+	}
+
+	pl, ok := p.pools[key]
+	if !ok {
+		pl = &pool{known: make(map[string]bool)}
+		p.pools[key] = pl
+	}
+	pl.lastUsed = now
+
+	// The questions fetched with the upstream token of a session may have
+	// already been seen through another session, so a few attempts are
+	// made before giving up.
+	for attempt := 0; ; attempt++ {
+		var available []opentrivia.Question
+		for _, q := range pl.questions {
+			if s == nil || !s.seen[q.Question] {
+				available = append(available, q)
+			}
+		}
+
+		if attempt == 0 {
+			p.countCache("questions", len(available) >= amount)
+		}
+
+		if len(available) >= amount {
+			p.rand.Shuffle(len(available), func(i, j int) {
+				available[i], available[j] = available[j], available[i]
+			})
+
+			results := available[:amount]
+			if s != nil {
+				for _, q := range results {
+					s.seen[q.Question] = true
+				}
+			}
+
+			return results, nil
+		}
+
+		if attempt == 3 {
+			if s != nil {
+				return nil, opentrivia.ErrTokenEmpty
+			}
+
+			return nil, opentrivia.ErrNoResults
+		}
+
+		var upstream opentrivia.Token
+		var resets int
+		if s != nil {
+			upstream = s.upstream
+			resets = s.resets
+		}
+
+		fetched, token, err := p.fetch(ctx, key, upstream, s != nil, amount-len(available))
+		if err != nil {
+			return nil, err
+		}
+
+		if s != nil && s.resets == resets {
+			s.upstream = token
+		}
+		pl.add(fetched)
+	}
+}
+
+// fetch requests at least n questions from upstream, creating an upstream
+// token first if one is needed but missing, or again if upstream no longer
+// knows it. It must be called with p.mu held, which is released while
+// waiting for upstream.
+func (p *Proxy) fetch(ctx context.Context, key poolKey, token opentrivia.Token, needsToken bool, n int) ([]opentrivia.Question, opentrivia.Token, error) {
+	p.mu.Unlock()
+	defer p.mu.Lock()
+
+	created := false
+	if needsToken && token == "" {
+		var err error
+		if token, err = p.createToken(ctx); err != nil {
+			return nil, "", err
+		}
+		created = true
+	}
+
+	options := &opentrivia.QuestionListOptions{
+		AllowUnknownCategory: true,
+		Category:             key.category,
+		Difficulty:           key.difficulty,
+		Limit:                maxUpstreamAmount,
+		Token:                token,
+		Type:                 key.kind,
+	}
+
+	questions, err := p.list(ctx, options)
+	if err == opentrivia.ErrTokenNotFound && needsToken && !created {
+		// Upstream deletes the tokens unused for six hours, which the
+		// sessions of the proxy may outlive.
+		if token, err = p.createToken(ctx); err != nil {
+			return nil, "", err
+		}
+		options.Token = token
+
+		questions, err = p.list(ctx, options)
+	}
+	if err == opentrivia.ErrNoResults || err == opentrivia.ErrTokenEmpty {
+		// There are less than maxUpstreamAmount questions left.
+		options.Limit = uint8(n)
+
+		questions, err = p.list(ctx, options)
+	}
+
+	return questions, token, err
+}
+
+// createToken creates an upstream token once the limiter allows it.
+func (p *Proxy) createToken(ctx context.Context) (opentrivia.Token, error) {
+	if err := p.limiter.wait(ctx); err != nil {
+		return "", err
+	}
+
+	return p.upstream.Token.CreateContext(ctx)
+}
+
+// list lists the upstream questions once the limiter allows it.
+func (p *Proxy) list(ctx context.Context, options *opentrivia.QuestionListOptions) ([]opentrivia.Question, error) {
+	if err := p.limiter.wait(ctx); err != nil {
+		return nil, err
+	}
+
+	return p.upstream.Question.ListContext(ctx, options)
+}
+
+// countCache emits a cache hit or miss to the metrics of the upstream
+// client.
+func (p *Proxy) countCache(cache string, hit bool) {
+	if p.upstream.Metrics == nil {
+		return
+	}
+
+	name := opentrivia.MetricCacheMisses
+	if hit {
+		name = opentrivia.MetricCacheHits
+	}
+
+	p.upstream.Metrics.Add(name, 1, opentrivia.Labels{"cache": cache})
+}
+
+func (p *Proxy) serveToken(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	now := p.now()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	switch q.Get("command") {
+	case "request":
+		token := opentrivia.Token(fmt.Sprintf("%016x%016x", p.rand.Uint64(), p.rand.Uint64()))
+		p.sessions[token] = &session{seen: make(map[string]bool), lastUsed: now}
+
+		writeJSON(w, &tokenResponse{
+			ResponseCode:    ResponseCodeSuccess,
+			ResponseMessage: "Token Generated Successfully!",
+			Token:           token,
+		})
+	case "reset":
+		token := opentrivia.Token(q.Get("token"))
+		s, ok := p.session(token, now)
+		if !ok {
+			writeJSON(w, &tokenResponse{ResponseCode: ResponseCodeTokenNotFound})
+			return
+		}
+
+		// A new upstream token is created when needed, which spares the
+		// rate limit of a reset request.
+		s.upstream = ""
+		s.seen = make(map[string]bool)
+		s.resets++
+
+		writeJSON(w, &tokenResponse{ResponseCode: ResponseCodeSuccess, Token: token})
+	default:
+		writeJSON(w, &tokenResponse{ResponseCode: ResponseCodeInvalidParameter})
+	}
+}
+
+func (p *Proxy) serveCategories(w http.ResponseWriter, r *http.Request) {
+	p.mu.Lock()
+	categories := p.categories
+	p.mu.Unlock()
+
+	p.countCache("categories", categories != nil)
+
+	if categories == nil {
+		err := p.limiter.wait(r.Context())
+		if err == nil {
+			categories, err = p.upstream.Category.List()
+		}
+		if err != nil {
+			http.Error(w, errors.Wrap(err, "server: error listing upstream categories").Error(), http.StatusBadGateway)
+			return
+		}
+
+		p.mu.Lock()
+		p.categories = categories
+		p.mu.Unlock()
+	}
+
+	writeJSON(w, &categoryResponse{Categories: categories})
+}
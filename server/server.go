@@ -0,0 +1,476 @@
+package server
+
+import (
+	"encoding/base64"
+	"fmt"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pinheirolucas/opentrivia"
+)
+
+// DefaultTokenTTL is the time a token lives without being used, as on the
+// Open Trivia API.
+const DefaultTokenTTL = 6 * time.Hour
+
+// Encodings of the encode parameter of api.php.
+const (
+	EncodingDefault   = ""
+	EncodingURLLegacy = "urlLegacy"
+	EncodingURL3986   = "url3986"
+	EncodingBase64    = "base64"
+)
+
+type token struct {
+	seen     map[string]bool
+	lastUsed time.Time
+}
+
+// Server is an http.Handler implementing the Open Trivia API: api.php,
+// api_token.php, api_category.php, api_count.php and api_count_global.php.
+// The questions are provided by a Store.
+//
+// The expired tokens, and the client IPs without recent requests, are
+// deleted while the server serves the requests.
+//
+// The fields of a Server must not be changed after it starts serving.
+type Server struct {
+	// TokenTTL is the time a token lives without being used. If zero,
+	// DefaultTokenTTL is used.
+	TokenTTL time.Duration
+
+	// RateLimit is the number of requests allowed for each client IP per
+	// RatePeriod. The requests above the limit are answered with
+	// ResponseCodeRateLimit. If zero, there is no limit.
+	RateLimit  int
+	RatePeriod time.Duration
+
+	// Now returns the current time. If nil, time.Now is used.
+	Now func() time.Time
+
+	store Store
+
+	mu       sync.Mutex
+	rand     *rand.Rand
+	tokens   map[opentrivia.Token]*token
+	requests map[string][]time.Time
+
+	lastSweep time.Time
+}
+
+// NewServer returns a Server serving the questions of the store.
+func NewServer(store Store) *Server {
+	return &Server{
+		store:    store,
+		rand:     rand.New(rand.NewSource(time.Now().UnixNano())),
+		tokens:   make(map[opentrivia.Token]*token),
+		requests: make(map[string][]time.Time),
+	}
+}
+
+// ServeHTTP implements the http.Handler interface.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.sweep(s.now())
+	s.mu.Unlock()
+
+	switch r.URL.Path {
+	case "/api.php":
+		if s.limited(r) {
+			writeQuestions(w, ResponseCodeRateLimit, nil)
+			return
+		}
+
+		s.serveQuestions(w, r)
+	case "/api_token.php":
+		if s.limited(r) {
+			writeJSON(w, &tokenResponse{ResponseCode: ResponseCodeRateLimit})
+			return
+		}
+
+		s.serveToken(w, r)
+	case "/api_category.php":
+		s.serveCategories(w, r)
+	case "/api_count.php":
+		s.serveCount(w, r)
+	case "/api_count_global.php":
+		s.serveGlobalCount(w, r)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (s *Server) now() time.Time {
+	if s.Now != nil {
+		return s.Now()
+	}
+
+	return time.Now()
+}
+
+// Size returns the number of tokens and of client IPs held by the server.
+func (s *Server) Size() (tokens, clients int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.tokens), len(s.requests)
+}
+
+func (s *Server) tokenTTL() time.Duration {
+	if s.TokenTTL == 0 {
+		return DefaultTokenTTL
+	}
+
+	return s.TokenTTL
+}
+
+// sweep deletes the expired tokens and the client IPs without requests in
+// the last RatePeriod, unless they were deleted less than sweepInterval
+// ago. It must be called with s.mu held.
+func (s *Server) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < sweepInterval {
+		return
+	}
+	s.lastSweep = now
+
+	ttl := s.tokenTTL()
+	for t, tk := range s.tokens {
+		if now.Sub(tk.lastUsed) > ttl {
+			delete(s.tokens, t)
+		}
+	}
+
+	for ip, times := range s.requests {
+		if len(times) == 0 || now.Sub(times[len(times)-1]) >= s.RatePeriod {
+			delete(s.requests, ip)
+		}
+	}
+}
+
+// limited records the request and reports whether its client IP is above
+// the rate limit.
+func (s *Server) limited(r *http.Request) bool {
+	if s.RateLimit <= 0 {
+		return false
+	}
+
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		ip = r.RemoteAddr
+	}
+
+	now := s.now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	recent := s.requests[ip][:0]
+	for _, t := range s.requests[ip] {
+		if now.Sub(t) < s.RatePeriod {
+			recent = append(recent, t)
+		}
+	}
+
+	if len(recent) >= s.RateLimit {
+		s.requests[ip] = recent
+		return true
+	}
+
+	s.requests[ip] = append(recent, now)
+	return false
+}
+
+// token returns the token if it exists and has not expired. It must be
+// called with s.mu held.
+func (s *Server) token(t opentrivia.Token, now time.Time) (*token, bool) {
+	tk, ok := s.tokens[t]
+	if !ok {
+		return nil, false
+	}
+	if now.Sub(tk.lastUsed) > s.tokenTTL() {
+		delete(s.tokens, t)
+		return nil, false
+	}
+
+	tk.lastUsed = now
+	return tk, true
+}
+
+// filter builds the store filter from the query. The returned code is
+// ResponseCodeNoResults if the category is unknown and
+// ResponseCodeInvalidParameter if a parameter is not valid.
+func (s *Server) filter(q url.Values) (Filter, int) {
+	var f Filter
+
+	f.Difficulty = opentrivia.QuestionDifficulty(q.Get("difficulty"))
+	switch f.Difficulty {
+	case "", opentrivia.QuestionDifficultyEasy, opentrivia.QuestionDifficultyMedium, opentrivia.QuestionDifficultyHard:
+	default:
+		return f, ResponseCodeInvalidParameter
+	}
+
+	f.Type = opentrivia.QuestionType(q.Get("type"))
+	switch f.Type {
+	case "", opentrivia.QuestionTypeMultiple, opentrivia.QuestionTypeTrueFalse:
+	default:
+		return f, ResponseCodeInvalidParameter
+	}
+
+	v := q.Get("category")
+	if v == "" {
+		return f, ResponseCodeSuccess
+	}
+
+	id, err := strconv.ParseUint(v, 10, 8)
+	if err != nil {
+		return f, ResponseCodeInvalidParameter
+	}
+
+	categories, err := s.store.Categories()
+	if err != nil {
+		return f, ResponseCodeNoResults
+	}
+
+	for _, c := range categories {
+		if c.ID == opentrivia.QuestionCategory(id) {
+			f.Category = c.Name
+			return f, ResponseCodeSuccess
+		}
+	}
+
+	return f, ResponseCodeNoResults
+}
+
+func (s *Server) serveQuestions(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	amount, err := strconv.Atoi(q.Get("amount"))
+	if err != nil || amount < 1 || amount > 50 {
+		writeQuestions(w, ResponseCodeInvalidParameter, nil)
+		return
+	}
+
+	encode := q.Get("encode")
+	switch encode {
+	case EncodingDefault, EncodingURLLegacy, EncodingURL3986, EncodingBase64:
+	default:
+		writeQuestions(w, ResponseCodeInvalidParameter, nil)
+		return
+	}
+
+	f, code := s.filter(q)
+	if code != ResponseCodeSuccess {
+		writeQuestions(w, code, nil)
+		return
+	}
+
+	matches, err := s.store.Questions(f)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var tk *token
+	if t := opentrivia.Token(q.Get("token")); t != "" {
+		var ok bool
+		if tk, ok = s.token(t, s.now()); !ok {
+			writeQuestions(w, ResponseCodeTokenNotFound, nil)
+			return
+		}
+	}
+
+	var available []opentrivia.Question
+	for _, question := range matches {
+		if tk == nil || !tk.seen[question.Question] {
+			available = append(available, question)
+		}
+	}
+
+	if len(available) < amount {
+		code := ResponseCodeNoResults
+		if tk != nil && len(matches) >= amount {
+			code = ResponseCodeTokenEmpty
+		}
+
+		writeQuestions(w, code, nil)
+		return
+	}
+
+	s.rand.Shuffle(len(available), func(i, j int) {
+		available[i], available[j] = available[j], available[i]
+	})
+
+	results := make([]opentrivia.Question, amount)
+	for i, question := range available[:amount] {
+		if tk != nil {
+			tk.seen[question.Question] = true
+		}
+
+		results[i] = encodeQuestion(question, encode)
+	}
+
+	writeQuestions(w, ResponseCodeSuccess, results)
+}
+
+func (s *Server) serveToken(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	now := s.now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch q.Get("command") {
+	case "request":
+		t := opentrivia.Token(fmt.Sprintf("%016x%016x", s.rand.Uint64(), s.rand.Uint64()))
+		s.tokens[t] = &token{seen: make(map[string]bool), lastUsed: now}
+
+		writeJSON(w, &tokenResponse{
+			ResponseCode:    ResponseCodeSuccess,
+			ResponseMessage: "Token Generated Successfully!",
+			Token:           t,
+		})
+	case "reset":
+		t := opentrivia.Token(q.Get("token"))
+		tk, ok := s.token(t, now)
+		if !ok {
+			writeJSON(w, &tokenResponse{ResponseCode: ResponseCodeTokenNotFound})
+			return
+		}
+
+		tk.seen = make(map[string]bool)
+		writeJSON(w, &tokenResponse{ResponseCode: ResponseCodeSuccess, Token: t})
+	default:
+		writeJSON(w, &tokenResponse{ResponseCode: ResponseCodeInvalidParameter})
+	}
+}
+
+func (s *Server) serveCategories(w http.ResponseWriter, r *http.Request) {
+	categories, err := s.store.Categories()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, &categoryResponse{Categories: categories})
+}
+
+func (s *Server) serveCount(w http.ResponseWriter, r *http.Request) {
+	v := r.URL.Query().Get("category")
+	id, err := strconv.ParseUint(v, 10, 8)
+	if err != nil {
+		writeJSON(w, map[string]string{"error": "No category selected."})
+		return
+	}
+
+	var questions []opentrivia.Question
+	if f, code := s.filter(url.Values{"category": {v}}); code == ResponseCodeSuccess {
+		if questions, err = s.store.Questions(f); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	count := countQuestions(questions)
+	count.Category = opentrivia.QuestionCategory(id)
+
+	writeJSON(w, &countResponse{
+		Category: count.Category,
+		Count:    count,
+	})
+}
+
+func (s *Server) serveGlobalCount(w http.ResponseWriter, r *http.Request) {
+	categories, err := s.store.Categories()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	resp := &globalCountResponse{
+		Categories: make(map[string]opentrivia.GlobalCount),
+	}
+	for _, c := range categories {
+		questions, err := s.store.Questions(Filter{Category: c.Name})
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		n := len(questions)
+		resp.Categories[strconv.Itoa(int(c.ID))] = opentrivia.GlobalCount{Total: n, Verified: n}
+		resp.Overall.Total += n
+		resp.Overall.Verified += n
+	}
+
+	writeJSON(w, resp)
+}
+
+func countQuestions(questions []opentrivia.Question) opentrivia.CategoryCount {
+	var count opentrivia.CategoryCount
+	for _, q := range questions {
+		count.Total++
+		switch opentrivia.QuestionDifficulty(q.Difficulty) {
+		case opentrivia.QuestionDifficultyEasy:
+			count.Easy++
+		case opentrivia.QuestionDifficultyMedium:
+			count.Medium++
+		case opentrivia.QuestionDifficultyHard:
+			count.Hard++
+		}
+	}
+
+	return count
+}
+
+// htmlReplacer encodes the text with the same entities as the Open Trivia
+// API.
+var htmlReplacer = strings.NewReplacer(
+	"&", "&amp;",
+	"<", "&lt;",
+	">", "&gt;",
+	`"`, "&quot;",
+	"'", "&#039;",
+)
+
+// encodeQuestion encodes the text fields of the question as requested by
+// the encode parameter. By default, they are HTML encoded.
+func encodeQuestion(q opentrivia.Question, encoding string) opentrivia.Question {
+	var encode func(string) string
+	switch encoding {
+	case EncodingURLLegacy:
+		encode = url.QueryEscape
+	case EncodingURL3986:
+		encode = func(s string) string {
+			return strings.Replace(url.QueryEscape(s), "+", "%20", -1)
+		}
+	case EncodingBase64:
+		encode = func(s string) string {
+			return base64.StdEncoding.EncodeToString([]byte(s))
+		}
+	default:
+		encode = htmlReplacer.Replace
+	}
+
+	incorrect := make([]string, len(q.IncorrectAnswers))
+	for i, a := range q.IncorrectAnswers {
+		incorrect[i] = encode(a)
+	}
+
+	return opentrivia.Question{
+		Category:         encode(q.Category),
+		Type:             encode(q.Type),
+		Difficulty:       encode(q.Difficulty),
+		Question:         encode(q.Question),
+		CorrectAnswer:    encode(q.CorrectAnswer),
+		IncorrectAnswers: incorrect,
+	}
+}
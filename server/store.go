@@ -0,0 +1,172 @@
+package server
+
+import (
+	"math"
+	"sort"
+	"sync"
+
+	"github.com/pinheirolucas/opentrivia"
+	"github.com/pkg/errors"
+)
+
+// firstCustomCategory is the ID given by MemoryStore to the first category
+// unknown to the opentrivia package.
+const firstCustomCategory = 100
+
+// maxCustomCategories is the number of category IDs left for the categories
+// unknown to the opentrivia package.
+const maxCustomCategories = math.MaxUint8 - firstCustomCategory + 1
+
+// Filter selects the questions of a Store. Empty fields match any
+// question.
+type Filter struct {
+	// Category is the name of the category, as returned by Categories.
+	Category   string
+	Difficulty opentrivia.QuestionDifficulty
+	Type       opentrivia.QuestionType
+}
+
+// Match reports whether the question is selected by the filter.
+func (f Filter) Match(q opentrivia.Question) bool {
+	return (f.Category == "" || q.Category == f.Category) &&
+		(f.Difficulty == "" || q.Difficulty == string(f.Difficulty)) &&
+		(f.Type == "" || q.Type == string(f.Type))
+}
+
+// Store provides the questions served by a Server.
+type Store interface {
+	// Questions returns all the questions selected by the filter.
+	Questions(f Filter) ([]opentrivia.Question, error)
+
+	// Categories returns the categories of the questions, ordered by ID.
+	Categories() ([]opentrivia.Category, error)
+}
+
+// MemoryStore is a Store that keeps the questions in memory. It is safe for
+// concurrent use.
+//
+// The categories named as in the Open Trivia API keep their IDs, while the
+// others are numbered from 100 in the order they are added, up to 255.
+type MemoryStore struct {
+	mu        sync.RWMutex
+	questions []opentrivia.Question
+	custom    map[string]opentrivia.QuestionCategory
+}
+
+// NewMemoryStore returns a MemoryStore holding the provided questions. It
+// returns an error if the questions have more categories than there are IDs
+// for them.
+func NewMemoryStore(questions []opentrivia.Question) (*MemoryStore, error) {
+	s := &MemoryStore{}
+	if err := s.Set(questions); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// Set replaces the questions of the store. It returns an error, and keeps
+// the previous questions, if the questions have more categories than there
+// are IDs for them.
+func (s *MemoryStore) Set(questions []opentrivia.Question) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	custom := make(map[string]opentrivia.QuestionCategory)
+	if err := addCategories(custom, questions); err != nil {
+		return err
+	}
+
+	s.questions = append([]opentrivia.Question(nil), questions...)
+	s.custom = custom
+
+	return nil
+}
+
+// Add adds questions to the store. It returns an error, and adds none of
+// the questions, if they would take the store over the number of category
+// IDs.
+func (s *MemoryStore) Add(questions ...opentrivia.Question) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	custom := make(map[string]opentrivia.QuestionCategory, len(s.custom))
+	for name, id := range s.custom {
+		custom[name] = id
+	}
+	if err := addCategories(custom, questions); err != nil {
+		return err
+	}
+
+	s.questions = append(s.questions, questions...)
+	s.custom = custom
+
+	return nil
+}
+
+// addCategories numbers the categories of the questions missing from
+// custom and unknown to the opentrivia package.
+func addCategories(custom map[string]opentrivia.QuestionCategory, questions []opentrivia.Question) error {
+	for _, q := range questions {
+		if _, ok := custom[q.Category]; ok || isKnownCategory(q.Category) {
+			continue
+		}
+		if len(custom) == maxCustomCategories {
+			return errors.Errorf("server: no category ID left for %q", q.Category)
+		}
+
+		custom[q.Category] = opentrivia.QuestionCategory(firstCustomCategory + len(custom))
+	}
+
+	return nil
+}
+
+// Questions implements the Store interface.
+func (s *MemoryStore) Questions(f Filter) ([]opentrivia.Question, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var questions []opentrivia.Question
+	for _, q := range s.questions {
+		if f.Match(q) {
+			questions = append(questions, q)
+		}
+	}
+
+	return questions, nil
+}
+
+// Categories implements the Store interface.
+func (s *MemoryStore) Categories() ([]opentrivia.Category, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	names := make(map[string]bool)
+	categories := []opentrivia.Category{}
+	for _, q := range s.questions {
+		if names[q.Category] {
+			continue
+		}
+		names[q.Category] = true
+
+		id, ok := s.custom[q.Category]
+		if !ok {
+			id, _ = opentrivia.ParseQuestionCategory(q.Category)
+		}
+
+		categories = append(categories, opentrivia.Category{ID: id, Name: q.Category})
+	}
+
+	sort.Slice(categories, func(i, j int) bool {
+		return categories[i].ID < categories[j].ID
+	})
+
+	return categories, nil
+}
+
+// isKnownCategory reports whether name is the Open Trivia API name of a
+// category.
+func isKnownCategory(name string) bool {
+	id, err := opentrivia.ParseQuestionCategory(name)
+	return err == nil && id.Name() == name
+}
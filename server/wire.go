@@ -0,0 +1,83 @@
+// Package server implements HTTP servers speaking the Open Trivia API wire
+// format, so the opentrivia.Client can be pointed at them via BaseURL.
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/pinheirolucas/opentrivia"
+	"github.com/pkg/errors"
+)
+
+// Response codes of the Open Trivia API.
+const (
+	ResponseCodeSuccess          = 0
+	ResponseCodeNoResults        = 1
+	ResponseCodeInvalidParameter = 2
+	ResponseCodeTokenNotFound    = 3
+	ResponseCodeTokenEmpty       = 4
+	ResponseCodeRateLimit        = 5
+)
+
+type questionResponse struct {
+	ResponseCode int                   `json:"response_code"`
+	Results      []opentrivia.Question `json:"results"`
+}
+
+type tokenResponse struct {
+	ResponseCode    int              `json:"response_code"`
+	ResponseMessage string           `json:"response_message,omitempty"`
+	Token           opentrivia.Token `json:"token"`
+}
+
+type categoryResponse struct {
+	Categories []opentrivia.Category `json:"trivia_categories"`
+}
+
+type countResponse struct {
+	Category opentrivia.QuestionCategory `json:"category_id"`
+	Count    opentrivia.CategoryCount    `json:"category_question_count"`
+}
+
+type globalCountResponse struct {
+	Overall    opentrivia.GlobalCount            `json:"overall"`
+	Categories map[string]opentrivia.GlobalCount `json:"categories"`
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeQuestions(w http.ResponseWriter, code int, results []opentrivia.Question) {
+	if results == nil {
+		results = []opentrivia.Question{}
+	}
+
+	writeJSON(w, &questionResponse{
+		ResponseCode: code,
+		Results:      results,
+	})
+}
+
+// responseCode returns the response code matching an error returned by the
+// opentrivia package. It returns false if there is no such code.
+func responseCode(err error) (int, bool) {
+	switch errors.Cause(err) {
+	case nil:
+		return ResponseCodeSuccess, true
+	case opentrivia.ErrNoResults:
+		return ResponseCodeNoResults, true
+	case opentrivia.ErrInvalidParameter:
+		return ResponseCodeInvalidParameter, true
+	case opentrivia.ErrTokenNotFound:
+		return ResponseCodeTokenNotFound, true
+	case opentrivia.ErrTokenEmpty:
+		return ResponseCodeTokenEmpty, true
+	case opentrivia.ErrRateLimit:
+		return ResponseCodeRateLimit, true
+	}
+
+	return 0, false
+}
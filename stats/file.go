@@ -0,0 +1,96 @@
+package stats
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// FileStore is a Store embedded in a single file, with a record per line
+// in JSON. The records are loaded in memory when the file is opened and
+// appended to the file as they are added. It is safe for concurrent use.
+type FileStore struct {
+	mem *MemoryStore
+
+	mu   sync.Mutex
+	file *os.File
+}
+
+// OpenFileStore opens the file store at path, creating the file if it does
+// not exist. The caller should call Close when finished.
+func OpenFileStore(path string) (*FileStore, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &FileStore{mem: NewMemoryStore(), file: f}
+
+	scanner := bufio.NewScanner(f)
+	line := 0
+	for scanner.Scan() {
+		line++
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		var r Record
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			f.Close()
+			return nil, errors.Wrapf(err, "stats: %s:%d", path, line)
+		}
+
+		s.mem.Add(r)
+	}
+	if err := scanner.Err(); err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// Add implements the Store interface. The record is written to the file
+// before Add returns.
+func (s *FileStore) Add(r Record) error {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.file == nil {
+		return errors.New("stats: file store closed")
+	}
+
+	if _, err := s.file.Write(append(data, '\n')); err != nil {
+		return err
+	}
+
+	return s.mem.Add(r)
+}
+
+// Records implements the Store interface.
+func (s *FileStore) Records(f Filter) ([]Record, error) {
+	return s.mem.Records(f)
+}
+
+// Close closes the file.
+func (s *FileStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.file == nil {
+		return nil
+	}
+
+	err := s.file.Close()
+	s.file = nil
+
+	return err
+}
@@ -0,0 +1,242 @@
+// Package stats records the answers of the players across games and
+// computes their statistics: accuracy by category and difficulty, streaks
+// and leaderboards.
+//
+//	s := stats.New(stats.NewMemoryStore())
+//	s.Record("ana", &question, answer, elapsed)
+//
+//	weekly, err := s.Weekly(10)
+//	ana, err := s.Player("ana")
+package stats
+
+import (
+	"html"
+	"sort"
+	"time"
+
+	"github.com/pinheirolucas/opentrivia"
+)
+
+// Record is a question answered by a player.
+type Record struct {
+	PlayerID    string                        `json:"player_id"`
+	Fingerprint string                        `json:"fingerprint"`
+	Category    opentrivia.QuestionCategory   `json:"category"`
+	Difficulty  opentrivia.QuestionDifficulty `json:"difficulty"`
+	Correct     bool                          `json:"correct"`
+	Elapsed     time.Duration                 `json:"elapsed"`
+	Time        time.Time                     `json:"time"`
+}
+
+// NewRecord returns the record of the answer of a player to the question,
+// answered at the provided time. The category is zero when it is unknown to
+// the client.
+func NewRecord(playerID string, q *opentrivia.Question, answer string, elapsed time.Duration, at time.Time) Record {
+	category, _ := opentrivia.ParseQuestionCategory(html.UnescapeString(q.Category))
+
+	return Record{
+		PlayerID:    playerID,
+		Fingerprint: q.Fingerprint(),
+		Category:    category,
+		Difficulty:  opentrivia.QuestionDifficulty(q.Difficulty),
+		Correct:     q.IsAnswerCorrect(answer),
+		Elapsed:     elapsed,
+		Time:        at,
+	}
+}
+
+// Accuracy counts the correct answers out of the answered questions.
+type Accuracy struct {
+	Answered int `json:"answered"`
+	Correct  int `json:"correct"`
+}
+
+// Rate returns the fraction of correct answers, zero if no question was
+// answered.
+func (a Accuracy) Rate() float64 {
+	if a.Answered == 0 {
+		return 0
+	}
+
+	return float64(a.Correct) / float64(a.Answered)
+}
+
+func (a *Accuracy) add(correct bool) {
+	a.Answered++
+	if correct {
+		a.Correct++
+	}
+}
+
+// PlayerStats are the statistics of a player.
+type PlayerStats struct {
+	PlayerID string `json:"player_id"`
+	Accuracy
+
+	// AverageTime is the average time to answer a question.
+	AverageTime time.Duration `json:"average_time"`
+
+	// Streak is the number of consecutive correct answers up to the last
+	// answer, and BestStreak the longest ever.
+	Streak     int `json:"streak"`
+	BestStreak int `json:"best_streak"`
+
+	ByCategory   map[opentrivia.QuestionCategory]Accuracy   `json:"by_category"`
+	ByDifficulty map[opentrivia.QuestionDifficulty]Accuracy `json:"by_difficulty"`
+}
+
+// Entry is the position of a player in a leaderboard.
+type Entry struct {
+	Rank     int    `json:"rank"`
+	PlayerID string `json:"player_id"`
+	Accuracy
+}
+
+// Stats computes the statistics of the records of a store.
+type Stats struct {
+	// Now returns the current time. If nil, time.Now is used.
+	Now func() time.Time
+
+	store Store
+}
+
+// New returns the Stats of the records of the store.
+func New(store Store) *Stats {
+	return &Stats{store: store}
+}
+
+func (s *Stats) now() time.Time {
+	if s.Now != nil {
+		return s.Now()
+	}
+
+	return time.Now()
+}
+
+// Record stores the answer of a player to the question, answered now.
+func (s *Stats) Record(playerID string, q *opentrivia.Question, answer string, elapsed time.Duration) (Record, error) {
+	r := NewRecord(playerID, q, answer, elapsed, s.now())
+	if err := s.store.Add(r); err != nil {
+		return Record{}, err
+	}
+
+	return r, nil
+}
+
+// Add stores a record.
+func (s *Stats) Add(r Record) error {
+	return s.store.Add(r)
+}
+
+// Player returns the statistics of a player.
+func (s *Stats) Player(playerID string) (PlayerStats, error) {
+	records, err := s.store.Records(Filter{PlayerID: playerID})
+	if err != nil {
+		return PlayerStats{}, err
+	}
+
+	ps := PlayerStats{
+		PlayerID:     playerID,
+		ByCategory:   make(map[opentrivia.QuestionCategory]Accuracy),
+		ByDifficulty: make(map[opentrivia.QuestionDifficulty]Accuracy),
+	}
+
+	var elapsed time.Duration
+	for _, r := range records {
+		ps.add(r.Correct)
+		elapsed += r.Elapsed
+
+		c := ps.ByCategory[r.Category]
+		c.add(r.Correct)
+		ps.ByCategory[r.Category] = c
+
+		d := ps.ByDifficulty[r.Difficulty]
+		d.add(r.Correct)
+		ps.ByDifficulty[r.Difficulty] = d
+
+		if r.Correct {
+			ps.Streak++
+			if ps.Streak > ps.BestStreak {
+				ps.BestStreak = ps.Streak
+			}
+		} else {
+			ps.Streak = 0
+		}
+	}
+
+	if len(records) > 0 {
+		ps.AverageTime = elapsed / time.Duration(len(records))
+	}
+
+	return ps, nil
+}
+
+// Leaderboard returns the players with the most correct answers since the
+// provided time, or ever if it is zero. Ties are broken by the accuracy.
+// A limit of zero returns every player.
+func (s *Stats) Leaderboard(since time.Time, limit int) ([]Entry, error) {
+	records, err := s.store.Records(Filter{Since: since})
+	if err != nil {
+		return nil, err
+	}
+
+	players := make(map[string]*Accuracy)
+	for _, r := range records {
+		a, ok := players[r.PlayerID]
+		if !ok {
+			a = &Accuracy{}
+			players[r.PlayerID] = a
+		}
+
+		a.add(r.Correct)
+	}
+
+	entries := make([]Entry, 0, len(players))
+	for id, a := range players {
+		entries = append(entries, Entry{PlayerID: id, Accuracy: *a})
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if a.Correct != b.Correct {
+			return a.Correct > b.Correct
+		}
+		if a.Rate() != b.Rate() {
+			return a.Rate() > b.Rate()
+		}
+
+		return a.PlayerID < b.PlayerID
+	})
+
+	if limit > 0 && len(entries) > limit {
+		entries = entries[:limit]
+	}
+
+	for i := range entries {
+		entries[i].Rank = i + 1
+		if i > 0 && entries[i].Accuracy == entries[i-1].Accuracy {
+			entries[i].Rank = entries[i-1].Rank
+		}
+	}
+
+	return entries, nil
+}
+
+// AllTime returns the all-time leaderboard.
+func (s *Stats) AllTime(limit int) ([]Entry, error) {
+	return s.Leaderboard(time.Time{}, limit)
+}
+
+// Weekly returns the leaderboard of the current week, which starts on
+// Monday at midnight in the location of the current time.
+func (s *Stats) Weekly(limit int) ([]Entry, error) {
+	return s.Leaderboard(WeekStart(s.now()), limit)
+}
+
+// WeekStart returns the midnight of the Monday of the week of t.
+func WeekStart(t time.Time) time.Time {
+	days := (int(t.Weekday()) + 6) % 7
+	y, m, d := t.Date()
+
+	return time.Date(y, m, d-days, 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,87 @@
+package stats
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Filter selects records. The zero Filter selects all records.
+type Filter struct {
+	// PlayerID selects the records of a single player.
+	PlayerID string
+
+	// Since selects the records answered at or after the time.
+	Since time.Time
+}
+
+// Match reports whether the record is selected by the filter.
+func (f Filter) Match(r Record) bool {
+	if f.PlayerID != "" && r.PlayerID != f.PlayerID {
+		return false
+	}
+	if !f.Since.IsZero() && r.Time.Before(f.Since) {
+		return false
+	}
+
+	return true
+}
+
+// Store persists the records.
+type Store interface {
+	// Add stores a record.
+	Add(r Record) error
+
+	// Records returns the records selected by the filter, ordered by time.
+	Records(f Filter) ([]Record, error)
+}
+
+// MemoryStore is a Store keeping the records in memory. It is safe for
+// concurrent use.
+type MemoryStore struct {
+	mu      sync.Mutex
+	records []Record
+	sorted  bool
+}
+
+// NewMemoryStore returns an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{sorted: true}
+}
+
+// Add implements the Store interface.
+func (s *MemoryStore) Add(r Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if n := len(s.records); n > 0 && r.Time.Before(s.records[n-1].Time) {
+		s.sorted = false
+	}
+	s.records = append(s.records, r)
+
+	return nil
+}
+
+// Records implements the Store interface.
+func (s *MemoryStore) Records(f Filter) ([]Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// The records usually arrive in order, so they are sorted only when
+	// needed.
+	if !s.sorted {
+		sort.SliceStable(s.records, func(i, j int) bool {
+			return s.records[i].Time.Before(s.records[j].Time)
+		})
+		s.sorted = true
+	}
+
+	records := []Record{}
+	for _, r := range s.records {
+		if f.Match(r) {
+			records = append(records, r)
+		}
+	}
+
+	return records, nil
+}
@@ -0,0 +1,157 @@
+package study
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pinheirolucas/opentrivia"
+)
+
+// Day is the unit of the review intervals.
+const Day = 24 * time.Hour
+
+// InitialEase is the ease factor of a new card.
+const InitialEase = 2.5
+
+// MinEase is the lowest ease factor of a card.
+const MinEase = 1.3
+
+// Card is the review state of a question.
+type Card struct {
+	Fingerprint string              `json:"fingerprint"`
+	Question    opentrivia.Question `json:"question"`
+
+	// Repetitions is the number of consecutive successful reviews.
+	Repetitions int `json:"repetitions"`
+
+	// Lapses is the number of failed reviews.
+	Lapses int `json:"lapses"`
+
+	// Ease grows with the easy reviews and lengthens the intervals.
+	Ease float64 `json:"ease"`
+
+	// Interval is the time between the last review and the next.
+	Interval time.Duration `json:"interval"`
+
+	Due      time.Time `json:"due"`
+	Reviewed time.Time `json:"reviewed"`
+}
+
+// NewCard returns the card of a question never reviewed.
+func NewCard(q opentrivia.Question) Card {
+	return Card{
+		Fingerprint: q.Fingerprint(),
+		Question:    q,
+		Ease:        InitialEase,
+	}
+}
+
+// Review returns the card after a review at the provided time, following
+// the SM-2 algorithm. The quality of the answer goes from 0, a complete
+// blackout, to 5, a perfect answer. Below 3, the review failed and the card
+// starts over.
+func (c Card) Review(quality int, at time.Time) Card {
+	if quality < 0 {
+		quality = 0
+	}
+	if quality > 5 {
+		quality = 5
+	}
+
+	if quality >= 3 {
+		switch c.Repetitions {
+		case 0:
+			c.Interval = Day
+		case 1:
+			c.Interval = 6 * Day
+		default:
+			days := math.Ceil(float64(c.Interval) / float64(Day) * c.Ease)
+			c.Interval = time.Duration(days) * Day
+		}
+		c.Repetitions++
+	} else {
+		c.Repetitions = 0
+		c.Lapses++
+		c.Interval = Day
+	}
+
+	q := float64(5 - quality)
+	c.Ease += 0.1 - q*(0.08+q*0.02)
+	if c.Ease < MinEase {
+		c.Ease = MinEase
+	}
+
+	c.Reviewed = at
+	c.Due = at.Add(c.Interval)
+
+	return c
+}
+
+// Store persists the cards.
+type Store interface {
+	// Get returns the card with the fingerprint. The boolean is false if
+	// there is no such card.
+	Get(fingerprint string) (Card, bool, error)
+
+	// Put stores the card, replacing the card with the same fingerprint.
+	Put(c Card) error
+
+	// Due returns the cards due at the provided time, the most overdue
+	// first.
+	Due(at time.Time) ([]Card, error)
+}
+
+// MemoryStore is a Store keeping the cards in memory. It is safe for
+// concurrent use.
+type MemoryStore struct {
+	mu    sync.Mutex
+	cards map[string]Card
+}
+
+// NewMemoryStore returns an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{cards: make(map[string]Card)}
+}
+
+// Get implements the Store interface.
+func (s *MemoryStore) Get(fingerprint string) (Card, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ok := s.cards[fingerprint]
+	return c, ok, nil
+}
+
+// Put implements the Store interface.
+func (s *MemoryStore) Put(c Card) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.cards[c.Fingerprint] = c
+	return nil
+}
+
+// Due implements the Store interface.
+func (s *MemoryStore) Due(at time.Time) ([]Card, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cards := []Card{}
+	for _, c := range s.cards {
+		if !c.Due.After(at) {
+			cards = append(cards, c)
+		}
+	}
+
+	sort.Slice(cards, func(i, j int) bool {
+		if !cards[i].Due.Equal(cards[j].Due) {
+			return cards[i].Due.Before(cards[j].Due)
+		}
+
+		return cards[i].Fingerprint < cards[j].Fingerprint
+	})
+
+	return cards, nil
+}
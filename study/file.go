@@ -0,0 +1,103 @@
+package study
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// FileStore is a Store embedded in a single file, with a card per line in
+// JSON. The cards are loaded in memory when the file is opened and appended
+// to the file as they are put, a later line replacing the earlier ones with
+// the same fingerprint. It is safe for concurrent use.
+type FileStore struct {
+	mem *MemoryStore
+
+	mu   sync.Mutex
+	file *os.File
+}
+
+// OpenFileStore opens the file store at path, creating the file if it does
+// not exist. The caller should call Close when finished.
+func OpenFileStore(path string) (*FileStore, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &FileStore{mem: NewMemoryStore(), file: f}
+
+	scanner := bufio.NewScanner(f)
+	line := 0
+	for scanner.Scan() {
+		line++
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		var c Card
+		if err := json.Unmarshal(scanner.Bytes(), &c); err != nil {
+			f.Close()
+			return nil, errors.Wrapf(err, "study: %s:%d", path, line)
+		}
+
+		s.mem.Put(c)
+	}
+	if err := scanner.Err(); err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// Get implements the Store interface.
+func (s *FileStore) Get(fingerprint string) (Card, bool, error) {
+	return s.mem.Get(fingerprint)
+}
+
+// Put implements the Store interface. The card is written to the file
+// before Put returns.
+func (s *FileStore) Put(c Card) error {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.file == nil {
+		return errors.New("study: file store closed")
+	}
+
+	if _, err := s.file.Write(append(data, '\n')); err != nil {
+		return err
+	}
+
+	return s.mem.Put(c)
+}
+
+// Due implements the Store interface.
+func (s *FileStore) Due(at time.Time) ([]Card, error) {
+	return s.mem.Due(at)
+}
+
+// Close closes the file.
+func (s *FileStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.file == nil {
+		return nil
+	}
+
+	err := s.file.Close()
+	s.file = nil
+
+	return err
+}
@@ -0,0 +1,172 @@
+// Package study turns the client into a study tool: the questions a player
+// misses come back at increasing intervals, following the SM-2 spaced
+// repetition algorithm, mixed with fresh questions.
+//
+//	s := study.NewScheduler(client.Question, study.NewMemoryStore())
+//
+//	items, err := s.Next(10)
+//	for _, item := range items {
+//		...
+//		s.Answer(&item.Question, answer)
+//	}
+package study
+
+import (
+	"time"
+
+	"github.com/pinheirolucas/opentrivia"
+)
+
+// Quality of the answers graded by Scheduler.Answer.
+const (
+	QualityCorrect   = 4
+	QualityIncorrect = 1
+)
+
+// Source provides the fresh questions. It is implemented by
+// *opentrivia.QuestionService.
+type Source interface {
+	List(options *opentrivia.QuestionListOptions) ([]opentrivia.Question, error)
+}
+
+// Item is a question of a study session.
+type Item struct {
+	Question opentrivia.Question
+
+	// Review is true for the questions due for review, false for the
+	// fresh ones.
+	Review bool
+}
+
+// Scheduler picks the questions of the study sessions and schedules their
+// reviews.
+type Scheduler struct {
+	// Options are used to fetch the fresh questions. If nil,
+	// opentrivia.DefaultQuestionListOptions is used.
+	Options *opentrivia.QuestionListOptions
+
+	// Now returns the current time. If nil, time.Now is used.
+	Now func() time.Time
+
+	source Source
+	store  Store
+}
+
+// NewScheduler returns a scheduler fetching the fresh questions from the
+// source and keeping the cards in the store.
+func NewScheduler(source Source, store Store) *Scheduler {
+	return &Scheduler{source: source, store: store}
+}
+
+func (s *Scheduler) now() time.Time {
+	if s.Now != nil {
+		return s.Now()
+	}
+
+	return time.Now()
+}
+
+// Next returns up to n questions: first the reviews due, then fresh
+// questions never reviewed. If there are reviews due but no fresh
+// questions left, only the reviews are returned. If n is not positive, no
+// questions are returned.
+func (s *Scheduler) Next(n int) ([]Item, error) {
+	if n <= 0 {
+		return []Item{}, nil
+	}
+
+	due, err := s.store.Due(s.now())
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]Item, 0, n)
+	for _, c := range due {
+		if len(items) == n {
+			return items, nil
+		}
+
+		items = append(items, Item{Question: c.Question, Review: true})
+	}
+
+	if len(items) == n {
+		return items, nil
+	}
+
+	options := *opentrivia.DefaultQuestionListOptions
+	if s.Options != nil {
+		options = *s.Options
+	}
+
+	limit := n - len(items)
+	if limit > 50 {
+		limit = 50
+	}
+	options.Limit = uint8(limit)
+
+	fresh, err := s.source.List(&options)
+	if err != nil {
+		if err == opentrivia.ErrNoResults && len(items) > 0 {
+			return items, nil
+		}
+
+		return nil, err
+	}
+
+	for _, q := range fresh {
+		if len(items) == n {
+			break
+		}
+
+		// The questions already known are reviewed on their schedule.
+		_, known, err := s.store.Get(q.Fingerprint())
+		if err != nil {
+			return nil, err
+		}
+		if known {
+			continue
+		}
+
+		items = append(items, Item{Question: q})
+	}
+
+	return items, nil
+}
+
+// Review grades the answer to the question with a quality from 0 to 5 and
+// schedules its next review.
+func (s *Scheduler) Review(q *opentrivia.Question, quality int) (Card, error) {
+	c, ok, err := s.store.Get(q.Fingerprint())
+	if err != nil {
+		return Card{}, err
+	}
+	if !ok {
+		c = NewCard(*q)
+	}
+
+	c = c.Review(quality, s.now())
+	if err := s.store.Put(c); err != nil {
+		return Card{}, err
+	}
+
+	return c, nil
+}
+
+// Answer grades the answer to the question with IsAnswerCorrect. A missed
+// question becomes a card due for review the next day. A fresh question
+// answered correctly is not scheduled, so the boolean is false and the
+// card is empty.
+func (s *Scheduler) Answer(q *opentrivia.Question, answer string) (Card, bool, error) {
+	if q.IsAnswerCorrect(answer) {
+		_, ok, err := s.store.Get(q.Fingerprint())
+		if err != nil || !ok {
+			return Card{}, false, err
+		}
+
+		c, err := s.Review(q, QualityCorrect)
+		return c, err == nil, err
+	}
+
+	c, err := s.Review(q, QualityIncorrect)
+	return c, err == nil, err
+}
@@ -0,0 +1,128 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pinheirolucas/opentrivia"
+	"github.com/pinheirolucas/opentrivia/adaptive"
+	"github.com/pinheirolucas/opentrivia/opentriviatest"
+	"github.com/pinheirolucas/opentrivia/stats"
+)
+
+func TestAdaptiveExpected(t *testing.T) {
+	t.Parallel()
+
+	if e := adaptive.Expected(1500, 1500); e != 0.5 {
+		t.Errorf("Expected %f, got %f", 0.5, e)
+	}
+
+	if adaptive.Expected(1700, 1300) <= adaptive.Expected(1500, 1300) {
+		t.Error("Expected a higher rating to have a higher chance")
+	}
+}
+
+func TestAdaptiveSelector(t *testing.T) {
+	t.Parallel()
+
+	t.Run("expect a new player to start on easy questions", func(t *testing.T) {
+		s := adaptive.NewSelector(nil)
+
+		if d := s.Next("ana").Difficulty; d != opentrivia.QuestionDifficultyEasy {
+			t.Errorf("Expected %s, got %s", opentrivia.QuestionDifficultyEasy, d)
+		}
+	})
+
+	t.Run("expect correct answers to raise the difficulty", func(t *testing.T) {
+		s := adaptive.NewSelector(nil)
+
+		for i := 0; i < 30; i++ {
+			s.Update("ana", opentrivia.QuestionCategoryComputer, opentrivia.QuestionDifficultyHard, true)
+		}
+
+		if d := s.Next("ana").Difficulty; d != opentrivia.QuestionDifficultyHard {
+			t.Errorf("Expected %s, got %s", opentrivia.QuestionDifficultyHard, d)
+		}
+	})
+
+	t.Run("expect the ratings to be exposed", func(t *testing.T) {
+		s := adaptive.NewSelector(nil)
+
+		q := opentriviatest.MultipleQuestion(opentrivia.QuestionCategoryHistory, opentrivia.QuestionDifficultyMedium, 1)
+		p := s.Answer("ana", &q, "Incorrect 1")
+
+		if p.Overall.Value != adaptive.InitialRating-adaptive.DefaultK/2 {
+			t.Errorf("Expected %d, got %f", adaptive.InitialRating-adaptive.DefaultK/2, p.Overall.Value)
+		}
+		if r := p.Category(opentrivia.QuestionCategoryHistory); r.Answered != 1 || r.Value >= adaptive.InitialRating {
+			t.Errorf("Expected a lower history rating, got %+v", r)
+		}
+		if r := s.Profile("ana").Category(opentrivia.QuestionCategoryComputer); r.Answered != 0 || r.Value != p.Overall.Value {
+			t.Errorf("Expected the overall rating for an unanswered category, got %+v", r)
+		}
+	})
+
+	t.Run("expect the history to be replayed", func(t *testing.T) {
+		s := adaptive.NewSelector(nil)
+
+		q := opentriviatest.MultipleQuestion(opentrivia.QuestionCategoryHistory, opentrivia.QuestionDifficultyMedium, 1)
+		s.Replay([]stats.Record{
+			stats.NewRecord("ana", &q, "Correct", time.Second, time.Now()),
+			stats.NewRecord("bob", &q, "Incorrect 1", time.Second, time.Now()),
+		})
+
+		if s.Profile("ana").Overall.Value <= s.Profile("bob").Overall.Value {
+			t.Error("Expected ana to be rated above bob")
+		}
+	})
+}
+
+func TestAdaptiveWeakest(t *testing.T) {
+	t.Parallel()
+
+	s := adaptive.NewSelector(adaptive.Weakest(adaptive.DefaultSuccess,
+		opentrivia.QuestionCategoryComputer,
+		opentrivia.QuestionCategoryHistory,
+	))
+
+	s.Update("ana", opentrivia.QuestionCategoryComputer, opentrivia.QuestionDifficultyEasy, true)
+	s.Update("ana", opentrivia.QuestionCategoryHistory, opentrivia.QuestionDifficultyEasy, false)
+
+	if c := s.Next("ana").Category; c != opentrivia.QuestionCategoryHistory {
+		t.Errorf("Expected %d, got %d", opentrivia.QuestionCategoryHistory, c)
+	}
+}
+
+func TestAdaptiveRandom(t *testing.T) {
+	t.Parallel()
+
+	server := opentriviatest.NewServer()
+	defer server.Close()
+
+	s := adaptive.NewSelector(adaptive.Weakest(adaptive.DefaultSuccess, opentrivia.QuestionCategoryHistory))
+
+	t.Run("expect the chosen category and difficulty", func(t *testing.T) {
+		q, err := s.Random(server.Client().Question, "ana")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if q.Category != opentrivia.QuestionCategoryHistory.Name() || q.Difficulty != string(opentrivia.QuestionDifficultyEasy) {
+			t.Errorf("Expected an easy history question, got %s %s", q.Difficulty, q.Category)
+		}
+	})
+
+	t.Run("expect any category when the chosen one is empty", func(t *testing.T) {
+		var questions []opentrivia.Question
+		for _, q := range opentriviatest.Questions() {
+			if q.Category != opentrivia.QuestionCategoryHistory.Name() {
+				questions = append(questions, q)
+			}
+		}
+		server.SetQuestions(questions)
+
+		if _, err := s.Random(server.Client().Question, "ana"); err != nil {
+			t.Errorf("Expected no error, got %s", err)
+		}
+	})
+}
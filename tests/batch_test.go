@@ -0,0 +1,235 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pinheirolucas/opentrivia"
+	"github.com/pinheirolucas/opentrivia/opentriviatest"
+)
+
+// inFlight is a RequestHook measuring the most requests sent at once.
+type inFlight struct {
+	mu      sync.Mutex
+	current int
+	max     int
+}
+
+func (h *inFlight) BeforeRequest(info opentrivia.RequestInfo) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.current++
+	if h.current > h.max {
+		h.max = h.current
+	}
+}
+
+func (h *inFlight) AfterResponse(info opentrivia.ResponseInfo) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.current--
+}
+
+func TestListBatch(t *testing.T) {
+	t.Parallel()
+
+	fake := opentriviatest.NewServer()
+	t.Cleanup(fake.Close)
+	fake.SetLatency(20 * time.Millisecond)
+
+	hook := &inFlight{}
+	c, err := opentrivia.NewClient(
+		opentrivia.WithBaseURL(fake.URL+"/"),
+		opentrivia.WithRequestHook(hook),
+		opentrivia.WithRateLimit(0),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	categories := []opentrivia.QuestionCategory{
+		opentrivia.QuestionCategoryHistory,
+		opentrivia.QuestionCategoryArt,
+		opentrivia.QuestionCategoryComputer,
+		opentrivia.QuestionCategoryMath,
+		opentrivia.QuestionCategorySport,
+		opentrivia.QuestionCategoryGeography,
+	}
+
+	options := make([]*opentrivia.QuestionListOptions, len(categories))
+	for i, category := range categories {
+		options[i] = &opentrivia.QuestionListOptions{Category: category, Limit: 2}
+	}
+	options = append(options, &opentrivia.QuestionListOptions{Type: "open"})
+
+	results := c.Question.ListBatch(context.Background(), options, 2)
+
+	t.Run("expect the results in order", func(t *testing.T) {
+		for i, category := range categories {
+			r := results[i]
+			if r.Err != nil {
+				t.Fatalf("Expected no error, got %s", r.Err)
+			}
+
+			for _, q := range r.Questions {
+				if q.Category != category.Name() {
+					t.Errorf("Expected %s, got %s", category.Name(), q.Category)
+				}
+			}
+		}
+	})
+
+	t.Run("expect an error per item", func(t *testing.T) {
+		if _, ok := results[len(results)-1].Err.(*opentrivia.ValidationError); !ok {
+			t.Errorf("Expected *opentrivia.ValidationError, got %v", results[len(results)-1].Err)
+		}
+	})
+
+	t.Run("expect the concurrency to be bounded", func(t *testing.T) {
+		if hook.max != 2 {
+			t.Errorf("Expected %d requests at once, got %d", 2, hook.max)
+		}
+	})
+
+	t.Run("expect the options to be left untouched", func(t *testing.T) {
+		if options[0].Limit != 2 || options[0].Token != "" {
+			t.Errorf("Expected the options to be copied, got %+v", options[0])
+		}
+	})
+}
+
+func TestListBatchRateLimit(t *testing.T) {
+	t.Parallel()
+
+	fake := opentriviatest.NewServer()
+	t.Cleanup(fake.Close)
+
+	const interval = 30 * time.Millisecond
+
+	c, err := opentrivia.NewClient(opentrivia.WithBaseURL(fake.URL+"/"), opentrivia.WithRateLimit(interval))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now()
+	results := c.Question.ListBatch(context.Background(), make([]*opentrivia.QuestionListOptions, 4), 4)
+
+	for i, r := range results {
+		if r.Err != nil {
+			t.Errorf("Expected no error for item %d, got %s", i, r.Err)
+		}
+	}
+
+	if elapsed := time.Since(start); elapsed < 3*interval {
+		t.Errorf("Expected the requests to share the rate limit, got %s in total", elapsed)
+	}
+}
+
+func TestListBatchServerRateLimit(t *testing.T) {
+	t.Parallel()
+
+	t.Run("expect the rate limit of the client to be honored", func(t *testing.T) {
+		t.Parallel()
+
+		const interval = 50 * time.Millisecond
+
+		fake := opentriviatest.NewServer()
+		t.Cleanup(fake.Close)
+		// The server is a little more lenient than the client, as a request
+		// may reach it a little later than it was sent.
+		fake.SetRateLimit(1, interval-10*time.Millisecond)
+
+		c, err := opentrivia.NewClient(opentrivia.WithBaseURL(fake.URL+"/"), opentrivia.WithRateLimit(interval))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for i, r := range c.Question.ListBatch(context.Background(), make([]*opentrivia.QuestionListOptions, 4), 4) {
+			if r.Err != nil {
+				t.Errorf("Expected no error for item %d, got %s", i, r.Err)
+			}
+		}
+	})
+
+	t.Run("expect the default rate limit without WithRateLimit", func(t *testing.T) {
+		t.Parallel()
+
+		fake := opentriviatest.NewServer()
+		t.Cleanup(fake.Close)
+		fake.SetRateLimit(1, opentrivia.DefaultBatchRateLimit-100*time.Millisecond)
+
+		c := fake.Client()
+
+		for i, r := range c.Question.ListBatch(context.Background(), make([]*opentrivia.QuestionListOptions, 2), 2) {
+			if r.Err != nil {
+				t.Errorf("Expected no error for item %d, got %s", i, r.Err)
+			}
+		}
+	})
+
+	t.Run("expect the items to fail without a rate limit", func(t *testing.T) {
+		t.Parallel()
+
+		fake := opentriviatest.NewServer()
+		t.Cleanup(fake.Close)
+		fake.SetRateLimit(1, time.Minute)
+
+		c, err := opentrivia.NewClient(opentrivia.WithBaseURL(fake.URL+"/"), opentrivia.WithRateLimit(0))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		failed := 0
+		for _, r := range c.Question.ListBatch(context.Background(), make([]*opentrivia.QuestionListOptions, 3), 3) {
+			if r.Err == opentrivia.ErrRateLimit {
+				failed++
+			}
+		}
+
+		if failed != 2 {
+			t.Errorf("Expected %d items to fail with %s, got %d", 2, opentrivia.ErrRateLimit, failed)
+		}
+	})
+}
+
+func TestListBatchCancel(t *testing.T) {
+	t.Parallel()
+
+	fake := opentriviatest.NewServer()
+	t.Cleanup(fake.Close)
+
+	c, err := opentrivia.NewClient(opentrivia.WithBaseURL(fake.URL+"/"), opentrivia.WithRateLimit(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	done := make(chan []opentrivia.BatchResult, 1)
+	go func() {
+		done <- c.Question.ListBatch(ctx, make([]*opentrivia.QuestionListOptions, 5), 2)
+	}()
+
+	var results []opentrivia.BatchResult
+	select {
+	case results = <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected the batch to stop once the context is done")
+	}
+
+	if results[0].Err != nil {
+		t.Errorf("Expected the first item to be sent, got %s", results[0].Err)
+	}
+
+	for i, r := range results[1:] {
+		if !errors.Is(r.Err, context.DeadlineExceeded) || r.Questions == nil {
+			t.Errorf("Expected item %d to fail with %s, got %v", i+1, context.DeadlineExceeded, r.Err)
+		}
+	}
+}
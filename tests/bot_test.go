@@ -0,0 +1,279 @@
+package tests
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pinheirolucas/opentrivia"
+	"github.com/pinheirolucas/opentrivia/bot"
+	"github.com/pinheirolucas/opentrivia/opentriviatest"
+)
+
+func newBot(t *testing.T) *bot.Bot {
+	server := opentriviatest.NewServer()
+	t.Cleanup(server.Close)
+
+	server.SetQuestions([]opentrivia.Question{
+		opentriviatest.MultipleQuestion(opentrivia.QuestionCategoryComputer, opentrivia.QuestionDifficultyMedium, 1),
+	})
+
+	return bot.New(server.Client())
+}
+
+// letterOf returns the letter of the choice with the text in the lines of
+// a question.
+func letterOf(lines []string, text string) string {
+	for _, l := range lines {
+		if strings.HasSuffix(l, ") "+text) {
+			return l[:1]
+		}
+	}
+
+	return ""
+}
+
+func TestBotHandle(t *testing.T) {
+	t.Parallel()
+
+	b := newBot(t)
+	trivia := bot.Message{Channel: "#trivia", User: "ana", Text: "!trivia computers"}
+
+	t.Run("expect messages without the prefix to be ignored", func(t *testing.T) {
+		if replies := b.Handle(bot.Message{Channel: "#trivia", User: "ana", Text: "hello"}); len(replies) != 0 {
+			t.Errorf("Expected no replies, got %v", replies)
+		}
+	})
+
+	t.Run("expect no answer without a question", func(t *testing.T) {
+		replies := b.Handle(bot.Message{Channel: "#trivia", User: "ana", Text: "!answer A"})
+		if len(replies) != 1 || !strings.Contains(replies[0], "no open question") {
+			t.Errorf("Expected no open question, got %v", replies)
+		}
+	})
+
+	question := b.Handle(trivia)
+	if len(question) != 5 {
+		t.Fatalf("Expected the question and %d choices, got %v", 4, question)
+	}
+
+	t.Run("expect a single open question per channel", func(t *testing.T) {
+		replies := b.Handle(trivia)
+		if len(replies) != 6 || replies[1] != question[0] {
+			t.Errorf("Expected the open question again, got %v", replies)
+		}
+	})
+
+	t.Run("expect a wrong letter to be rejected once", func(t *testing.T) {
+		wrong := letterOf(question, "Incorrect 1")
+
+		replies := b.Handle(bot.Message{Channel: "#trivia", User: "bob", Text: "!answer " + wrong})
+		if len(replies) != 1 || !strings.Contains(replies[0], "not it") {
+			t.Errorf("Expected a wrong answer, got %v", replies)
+		}
+
+		replies = b.Handle(bot.Message{Channel: "#trivia", User: "bob", Text: "!answer Correct"})
+		if len(replies) != 1 || !strings.Contains(replies[0], "already answered") {
+			t.Errorf("Expected a single answer, got %v", replies)
+		}
+	})
+
+	t.Run("expect a reaction to answer with its letter", func(t *testing.T) {
+		letter := strings.ToLower(letterOf(question, "Correct"))
+
+		replies := b.Handle(bot.Message{Channel: "#trivia", User: "ana", Reaction: ":regional_indicator_" + letter + ":"})
+		if len(replies) != 1 || !strings.HasPrefix(replies[0], "ana got it!") {
+			t.Errorf("Expected a correct answer, got %v", replies)
+		}
+	})
+
+	t.Run("expect the scores of the channel", func(t *testing.T) {
+		replies := b.Handle(bot.Message{Channel: "#trivia", User: "bob", Text: "!score"})
+		if len(replies) != 2 || replies[1] != "1. ana 2" {
+			t.Errorf("Expected ana with %d points, got %v", 2, replies)
+		}
+
+		replies = b.Handle(bot.Message{Channel: "#other", User: "bob", Text: "!score"})
+		if len(replies) != 1 || replies[0] != "No scores yet." {
+			t.Errorf("Expected no scores, got %v", replies)
+		}
+	})
+
+	t.Run("expect the categories", func(t *testing.T) {
+		replies := b.Handle(bot.Message{Channel: "#trivia", User: "bob", Text: "!categories"})
+		if len(replies) != 1 || !strings.Contains(replies[0], "18 Science: Computers") {
+			t.Errorf("Expected the categories, got %v", replies)
+		}
+	})
+}
+
+func TestBotCloseQuestion(t *testing.T) {
+	t.Parallel()
+
+	t.Run("expect skip to reveal the answer", func(t *testing.T) {
+		t.Parallel()
+
+		b := newBot(t)
+		b.Handle(bot.Message{Channel: "#trivia", User: "ana", Text: "!trivia"})
+
+		replies := b.Handle(bot.Message{Channel: "#trivia", User: "ana", Text: "!skip"})
+		if len(replies) != 1 || replies[0] != "Skipped. The answer was Correct." {
+			t.Errorf("Expected the answer to be revealed, got %v", replies)
+		}
+
+		replies = b.Handle(bot.Message{Channel: "#trivia", User: "ana", Text: "!skip"})
+		if len(replies) != 1 || !strings.Contains(replies[0], "no open question") {
+			t.Errorf("Expected no open question, got %v", replies)
+		}
+	})
+
+	t.Run("expect the question to close once every player answered", func(t *testing.T) {
+		t.Parallel()
+
+		b := newBot(t)
+		question := b.Handle(bot.Message{Channel: "#trivia", User: "ana", Text: "!trivia"})
+		b.Handle(bot.Message{Channel: "#trivia", User: "bob", Text: "!score"})
+
+		replies := b.Handle(bot.Message{Channel: "#trivia", User: "ana", Text: "!answer " + letterOf(question, "Incorrect 1")})
+		if len(replies) != 1 {
+			t.Errorf("Expected the question to stay open for bob, got %v", replies)
+		}
+
+		replies = b.Handle(bot.Message{Channel: "#trivia", User: "bob", Text: "!answer " + letterOf(question, "Incorrect 2")})
+		if len(replies) != 2 || replies[1] != "Everyone answered. The answer was Correct." {
+			t.Errorf("Expected the answer to be revealed, got %v", replies)
+		}
+
+		replies = b.Handle(bot.Message{Channel: "#trivia", User: "ana", Text: "!trivia"})
+		if len(replies) != 5 {
+			t.Errorf("Expected a new question, got %v", replies)
+		}
+	})
+
+	t.Run("expect the question to time out", func(t *testing.T) {
+		t.Parallel()
+
+		now := time.Now()
+		b := newBot(t)
+		b.Timeout = 30 * time.Second
+		b.Now = func() time.Time { return now }
+
+		b.Handle(bot.Message{Channel: "#trivia", User: "ana", Text: "!trivia"})
+
+		now = now.Add(31 * time.Second)
+
+		replies := b.Handle(bot.Message{Channel: "#trivia", User: "bob", Text: "!trivia"})
+		if len(replies) != 6 || replies[0] != "Time is up! The answer was Correct." {
+			t.Errorf("Expected the answer to be revealed and a new question, got %v", replies)
+		}
+	})
+}
+
+func TestReactionLetter(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]byte{
+		"🇧":                      'B',
+		":regional_indicator_c:": 'C',
+		"a":                      'A',
+	}
+
+	for reaction, expected := range cases {
+		if letter, ok := bot.ReactionLetter(reaction); !ok || letter != expected {
+			t.Errorf("Expected %c for %q, got %c", expected, reaction, letter)
+		}
+	}
+
+	if _, ok := bot.ReactionLetter(":tada:"); ok {
+		t.Error("Expected other reactions to be ignored")
+	}
+}
+
+func TestBotLineTransport(t *testing.T) {
+	t.Parallel()
+
+	b := newBot(t)
+
+	inR, inW := io.Pipe()
+	outR, outW := io.Pipe()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- b.Serve(bot.NewLineTransport(inR, outW))
+		outW.Close()
+	}()
+
+	out := bufio.NewReader(outR)
+	readLine := func() string {
+		line, err := out.ReadString('\n')
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return strings.TrimRight(line, "\r\n")
+	}
+
+	io.WriteString(inW, "PING :irc.example.com\r\n")
+	if line := readLine(); line != "PONG :irc.example.com" {
+		t.Errorf("Expected PONG, got %q", line)
+	}
+
+	io.WriteString(inW, ":ana!ana@example.com PRIVMSG #trivia :!trivia\r\n")
+
+	var question []string
+	for i := 0; i < 5; i++ {
+		line := readLine()
+		if !strings.HasPrefix(line, "PRIVMSG #trivia :") {
+			t.Fatalf("Expected a message to #trivia, got %q", line)
+		}
+
+		question = append(question, strings.TrimPrefix(line, "PRIVMSG #trivia :"))
+	}
+
+	io.WriteString(inW, ":bob PRIVMSG #trivia :!answer "+letterOf(question, "Correct")+"\r\n")
+	if line := readLine(); !strings.HasPrefix(line, "PRIVMSG #trivia :bob got it!") {
+		t.Errorf("Expected bob to get it, got %q", line)
+	}
+
+	inW.Close()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Expected no error, got %s", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Error("Expected Serve to return at the end of the input")
+	}
+}
+
+func TestBotWebhook(t *testing.T) {
+	t.Parallel()
+
+	ts := httptest.NewServer(newBot(t).WebhookHandler())
+	defer ts.Close()
+
+	body := `{"channel":"#trivia","user":"ana","text":"!help"}`
+	resp, err := http.Post(ts.URL, "application/json", bytes.NewBufferString(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	var out struct {
+		Replies []string `json:"replies"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(out.Replies) != 5 || !strings.HasPrefix(out.Replies[0], "!trivia") {
+		t.Errorf("Expected the help, got %v", out.Replies)
+	}
+}
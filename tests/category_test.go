@@ -0,0 +1,61 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/pinheirolucas/opentrivia"
+	"github.com/pinheirolucas/opentrivia/opentriviatest"
+)
+
+func TestCategoryServiceList(t *testing.T) {
+	t.Parallel()
+
+	categories, err := client.Category.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(categories) != 24 {
+		t.Fatalf("Expected 24 categories, got %d", len(categories))
+	}
+
+	if categories[1].ID != opentrivia.QuestionCategoryBook || categories[1].Name != "Entertainment: Books" {
+		t.Errorf("Unexpected category %+v", categories[1])
+	}
+}
+
+func TestCategoryServiceCount(t *testing.T) {
+	t.Parallel()
+
+	count, err := client.Category.Count(opentrivia.QuestionCategoryComputer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const perDifficulty = 2 * opentriviatest.QuestionsPerGroup
+	expected := opentrivia.CategoryCount{
+		Category: opentrivia.QuestionCategoryComputer,
+		Total:    3 * perDifficulty,
+		Easy:     perDifficulty,
+		Medium:   perDifficulty,
+		Hard:     perDifficulty,
+	}
+
+	if count != expected {
+		t.Errorf("Expected %+v, got %+v", expected, count)
+	}
+}
+
+func TestCategoryServiceGlobalCount(t *testing.T) {
+	t.Parallel()
+
+	count, err := client.Category.GlobalCount()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := len(opentriviatest.Questions())
+	if count.Total != expected || count.Verified != expected {
+		t.Errorf("Expected %d questions, got %+v", expected, count)
+	}
+}
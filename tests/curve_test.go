@@ -0,0 +1,208 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/pinheirolucas/opentrivia"
+	"github.com/pinheirolucas/opentrivia/opentriviatest"
+)
+
+func TestLinearCurve(t *testing.T) {
+	t.Parallel()
+
+	expected := []opentrivia.QuestionDifficulty{
+		opentrivia.QuestionDifficultyEasy,
+		opentrivia.QuestionDifficultyEasy,
+		opentrivia.QuestionDifficultyMedium,
+		opentrivia.QuestionDifficultyMedium,
+		opentrivia.QuestionDifficultyHard,
+		opentrivia.QuestionDifficultyHard,
+	}
+
+	for i, d := range expected {
+		if result := opentrivia.LinearCurve.Difficulty(i, len(expected)); result != d {
+			t.Errorf("Expected %s at position %d, got %s", d, i, result)
+		}
+	}
+}
+
+func TestStepCurve(t *testing.T) {
+	t.Parallel()
+
+	curve := opentrivia.StepCurve(5, 10)
+
+	cases := map[int]opentrivia.QuestionDifficulty{
+		0:  opentrivia.QuestionDifficultyEasy,
+		4:  opentrivia.QuestionDifficultyEasy,
+		5:  opentrivia.QuestionDifficultyMedium,
+		9:  opentrivia.QuestionDifficultyMedium,
+		10: opentrivia.QuestionDifficultyHard,
+		14: opentrivia.QuestionDifficultyHard,
+	}
+
+	for position, d := range cases {
+		if result := curve.Difficulty(position, 15); result != d {
+			t.Errorf("Expected %s at position %d, got %s", d, position, result)
+		}
+	}
+}
+
+func TestCustomCurve(t *testing.T) {
+	t.Parallel()
+
+	curve := opentrivia.CustomCurve(
+		opentrivia.QuestionDifficultyHard,
+		opentrivia.QuestionDifficultyEasy,
+	)
+
+	if result := curve.Difficulty(0, 3); result != opentrivia.QuestionDifficultyHard {
+		t.Errorf("Expected %s, got %s", opentrivia.QuestionDifficultyHard, result)
+	}
+
+	if result := curve.Difficulty(2, 3); result != opentrivia.QuestionDifficultyEasy {
+		t.Errorf("Expected the last difficulty to be repeated, got %s", result)
+	}
+}
+
+// newLadderClient returns a client for a server that has no hard questions.
+func newLadderClient(t *testing.T) *opentrivia.Client {
+	server := opentriviatest.NewServer()
+	t.Cleanup(server.Close)
+
+	var questions []opentrivia.Question
+	for _, q := range opentriviatest.Questions() {
+		if q.Difficulty != string(opentrivia.QuestionDifficultyHard) {
+			questions = append(questions, q)
+		}
+	}
+	server.SetQuestions(questions)
+
+	return server.Client()
+}
+
+func TestQuestionServiceLadder(t *testing.T) {
+	t.Parallel()
+
+	c := newLadderClient(t)
+
+	t.Run("expect the questions to follow the curve", func(t *testing.T) {
+		t.Parallel()
+
+		options := &opentrivia.QuestionLadderOptions{
+			Curve:  opentrivia.StepCurve(2, 10),
+			Length: 4,
+		}
+
+		ladder, err := c.Question.Ladder(options)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(ladder) != 4 {
+			t.Fatalf("Expected 4 questions, got %d", len(ladder))
+		}
+
+		for i, v := range ladder {
+			if v.Position != i {
+				t.Errorf("Expected position %d, got %d", i, v.Position)
+			}
+			if string(v.Difficulty) != v.Question.Difficulty {
+				t.Errorf("Expected difficulty %s, got %s", v.Difficulty, v.Question.Difficulty)
+			}
+		}
+	})
+
+	t.Run("expect to fall back to an adjacent difficulty", func(t *testing.T) {
+		t.Parallel()
+
+		options := &opentrivia.QuestionLadderOptions{
+			Curve:  opentrivia.CustomCurve(opentrivia.QuestionDifficultyHard),
+			Length: 3,
+		}
+
+		ladder, err := c.Question.Ladder(options)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, v := range ladder {
+			if v.Difficulty != opentrivia.QuestionDifficultyHard {
+				t.Errorf("Expected the requested difficulty to be kept, got %s", v.Difficulty)
+			}
+			if v.Question.Difficulty != string(opentrivia.QuestionDifficultyMedium) {
+				t.Errorf("Expected a medium question, got %s", v.Question.Difficulty)
+			}
+		}
+	})
+	t.Run("expect partial results to be kept", func(t *testing.T) {
+		t.Parallel()
+
+		ladder := ladderWith(t, map[opentrivia.QuestionDifficulty]int{
+			opentrivia.QuestionDifficultyEasy:   4,
+			opentrivia.QuestionDifficultyMedium: 10,
+		}, 5)
+
+		counts := make(map[string]int)
+		for _, v := range ladder {
+			counts[v.Question.Difficulty]++
+		}
+
+		if counts["easy"] != 4 || counts["medium"] != 1 {
+			t.Errorf("Expected 4 easy questions and 1 medium, got %v", counts)
+		}
+	})
+
+	t.Run("expect a mix of difficulties to fill the ladder", func(t *testing.T) {
+		t.Parallel()
+
+		ladder := ladderWith(t, map[opentrivia.QuestionDifficulty]int{
+			opentrivia.QuestionDifficultyEasy:   2,
+			opentrivia.QuestionDifficultyMedium: 2,
+			opentrivia.QuestionDifficultyHard:   2,
+		}, 5)
+
+		seen := make(map[string]bool)
+		for _, v := range ladder {
+			if seen[v.Question.Question] {
+				t.Errorf("Expected no repeated question, got %q twice", v.Question.Question)
+			}
+			seen[v.Question.Question] = true
+		}
+	})
+}
+
+// ladderWith returns an easy ladder of the length from a server with the
+// provided number of questions of each difficulty.
+func ladderWith(t *testing.T, counts map[opentrivia.QuestionDifficulty]int, length uint8) []opentrivia.LadderQuestion {
+	server := opentriviatest.NewServer()
+	t.Cleanup(server.Close)
+
+	var questions []opentrivia.Question
+	for d, n := range counts {
+		for i := 0; i < n; i++ {
+			questions = append(questions, opentriviatest.MultipleQuestion(opentrivia.QuestionCategoryArt, d, i))
+		}
+	}
+	server.SetQuestions(questions)
+
+	c := server.Client()
+	token, err := c.Token.Create()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ladder, err := c.Question.Ladder(&opentrivia.QuestionLadderOptions{
+		Curve:  opentrivia.CustomCurve(opentrivia.QuestionDifficultyEasy),
+		Length: length,
+		Token:  token,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(ladder) != int(length) {
+		t.Fatalf("Expected %d questions, got %d", length, len(ladder))
+	}
+
+	return ladder
+}
@@ -0,0 +1,365 @@
+package tests
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pinheirolucas/opentrivia"
+	"github.com/pinheirolucas/opentrivia/game"
+	"github.com/pinheirolucas/opentrivia/opentriviatest"
+)
+
+// fakeClock is a clock moved by hand.
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+}
+
+// fakeSource returns its questions and records the options it received.
+type fakeSource struct {
+	questions []opentrivia.Question
+	options   []*opentrivia.QuestionListOptions
+}
+
+func (s *fakeSource) List(options *opentrivia.QuestionListOptions) ([]opentrivia.Question, error) {
+	s.options = append(s.options, options)
+	return s.questions, nil
+}
+
+func newGameSource() *fakeSource {
+	return &fakeSource{questions: []opentrivia.Question{
+		opentriviatest.MultipleQuestion(opentrivia.QuestionCategoryComputer, opentrivia.QuestionDifficultyEasy, 1),
+		opentriviatest.MultipleQuestion(opentrivia.QuestionCategoryComputer, opentrivia.QuestionDifficultyHard, 1),
+	}}
+}
+
+func TestGameScoring(t *testing.T) {
+	t.Parallel()
+
+	clock := newFakeClock()
+	g := game.New(newGameSource(), game.Config{TimeLimit: 10 * time.Second, Clock: clock})
+	defer g.Close()
+
+	g.AddPlayer("ana", "Ana")
+	g.AddPlayer("bob", "Bob")
+
+	if err := g.StartRound(); err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+
+	if _, err := g.NextQuestion(); err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+
+	t.Run("expect a speed bonus for an instant answer", func(t *testing.T) {
+		a, err := g.Answer("ana", "Correct")
+		if err != nil {
+			t.Fatalf("Expected no error, got %s", err)
+		}
+
+		if a.Points != 150 {
+			t.Errorf("Expected %d points, got %d", 150, a.Points)
+		}
+	})
+
+	t.Run("expect a smaller bonus for a slower answer", func(t *testing.T) {
+		clock.Advance(5 * time.Second)
+
+		a, err := g.Answer("bob", "Correct")
+		if err != nil {
+			t.Fatalf("Expected no error, got %s", err)
+		}
+
+		if a.Points != 125 {
+			t.Errorf("Expected %d points, got %d", 125, a.Points)
+		}
+	})
+
+	t.Run("expect the difficulty and the streak to be weighted", func(t *testing.T) {
+		if _, err := g.NextQuestion(); err != nil {
+			t.Fatalf("Expected no error, got %s", err)
+		}
+
+		clock.Advance(10 * time.Second)
+
+		a, err := g.Answer("ana", "Correct")
+		if err != nil {
+			t.Fatalf("Expected no error, got %s", err)
+		}
+
+		if a.Points != 310 {
+			t.Errorf("Expected %d points, got %d", 310, a.Points)
+		}
+		if a.Streak != 2 {
+			t.Errorf("Expected a streak of %d, got %d", 2, a.Streak)
+		}
+	})
+
+	t.Run("expect late answers to be rejected", func(t *testing.T) {
+		clock.Advance(time.Second)
+
+		if _, err := g.Answer("bob", "Correct"); err != game.ErrTooLate {
+			t.Errorf("Expected %s, got %v", game.ErrTooLate, err)
+		}
+	})
+
+	t.Run("expect the players without answer to lose their streaks", func(t *testing.T) {
+		g.EndQuestion()
+
+		bob, _ := g.Player("bob")
+		if bob.Streak != 0 {
+			t.Errorf("Expected a streak of %d, got %d", 0, bob.Streak)
+		}
+		if bob.BestStreak != 1 {
+			t.Errorf("Expected a best streak of %d, got %d", 1, bob.BestStreak)
+		}
+	})
+
+	t.Run("expect the leaderboard to be ordered by score", func(t *testing.T) {
+		leaderboard := g.Leaderboard()
+		if len(leaderboard) != 2 {
+			t.Fatalf("Expected %d players, got %d", 2, len(leaderboard))
+		}
+
+		if leaderboard[0].ID != "ana" || leaderboard[0].Score != 460 {
+			t.Errorf("Expected ana with %d points first, got %s with %d", 460, leaderboard[0].ID, leaderboard[0].Score)
+		}
+	})
+}
+
+func TestGameAnswerErrors(t *testing.T) {
+	t.Parallel()
+
+	g := game.New(newGameSource(), game.Config{Clock: newFakeClock()})
+	defer g.Close()
+
+	g.AddPlayer("ana", "Ana")
+
+	t.Run("expect duplicated players to be rejected", func(t *testing.T) {
+		if err := g.AddPlayer("ana", "Ana"); err != game.ErrPlayerExists {
+			t.Errorf("Expected %s, got %v", game.ErrPlayerExists, err)
+		}
+	})
+
+	t.Run("expect an error when there is no question", func(t *testing.T) {
+		if _, err := g.Answer("ana", "Correct"); err != game.ErrNoQuestion {
+			t.Errorf("Expected %s, got %v", game.ErrNoQuestion, err)
+		}
+	})
+
+	g.StartRound()
+	g.NextQuestion()
+
+	t.Run("expect unknown players to be rejected", func(t *testing.T) {
+		if _, err := g.Answer("bob", "Correct"); err != game.ErrUnknownPlayer {
+			t.Errorf("Expected %s, got %v", game.ErrUnknownPlayer, err)
+		}
+	})
+
+	t.Run("expect a single answer per player", func(t *testing.T) {
+		if _, err := g.Answer("ana", "Incorrect 1"); err != nil {
+			t.Fatalf("Expected no error, got %s", err)
+		}
+
+		if _, err := g.Answer("ana", "Correct"); err != game.ErrAlreadyAnswered {
+			t.Errorf("Expected %s, got %v", game.ErrAlreadyAnswered, err)
+		}
+	})
+
+	t.Run("expect a round in progress not to be restarted", func(t *testing.T) {
+		if err := g.StartRound(); err != game.ErrRoundInProgress {
+			t.Errorf("Expected %s, got %v", game.ErrRoundInProgress, err)
+		}
+	})
+}
+
+func TestGameRounds(t *testing.T) {
+	t.Parallel()
+
+	source := newGameSource()
+	hard := &opentrivia.QuestionListOptions{Limit: 2, Difficulty: opentrivia.QuestionDifficultyHard}
+
+	g := game.New(source, game.Config{
+		Rounds: []game.Round{{}, {Options: hard}},
+		Clock:  newFakeClock(),
+	})
+	defer g.Close()
+
+	for round := 1; round <= 2; round++ {
+		if err := g.StartRound(); err != nil {
+			t.Fatalf("Expected no error, got %s", err)
+		}
+
+		for i := 1; i <= len(source.questions); i++ {
+			q, err := g.NextQuestion()
+			if err != nil {
+				t.Fatalf("Expected no error, got %s", err)
+			}
+
+			if q.Number != i || q.Total != len(source.questions) {
+				t.Errorf("Expected question %d of %d, got %d of %d", i, len(source.questions), q.Number, q.Total)
+			}
+		}
+
+		if _, err := g.NextQuestion(); err != game.ErrRoundOver {
+			t.Errorf("Expected %s, got %v", game.ErrRoundOver, err)
+		}
+
+		if err := g.EndRound(); err != nil {
+			t.Fatalf("Expected no error, got %s", err)
+		}
+	}
+
+	if source.options[0] != nil {
+		t.Errorf("Expected the default options on the first round, got %+v", source.options[0])
+	}
+	if source.options[1] == hard || source.options[1].Difficulty != hard.Difficulty {
+		t.Errorf("Expected a copy of the round options, got %+v", source.options[1])
+	}
+
+	if !g.Over() {
+		t.Error("Expected the game to be over")
+	}
+
+	if err := g.StartRound(); err != game.ErrGameOver {
+		t.Errorf("Expected %s, got %v", game.ErrGameOver, err)
+	}
+}
+
+func TestGameEvents(t *testing.T) {
+	t.Parallel()
+
+	g := game.New(newGameSource(), game.Config{Clock: newFakeClock()})
+	events := g.Events()
+
+	g.AddPlayer("ana", "Ana")
+	g.StartRound()
+	q, _ := g.NextQuestion()
+	g.Answer("ana", q.Question.CorrectAnswer)
+	g.EndRound()
+	g.Close()
+
+	expected := []game.EventType{
+		game.EventRoundStarted,
+		game.EventQuestionStarted,
+		game.EventAnswerReceived,
+		game.EventQuestionEnded,
+		game.EventRoundEnded,
+		game.EventGameEnded,
+	}
+
+	var received []game.Event
+	for e := range events {
+		received = append(received, e)
+	}
+
+	if len(received) != len(expected) {
+		t.Fatalf("Expected %d events, got %d", len(expected), len(received))
+	}
+
+	for i, e := range received {
+		if e.Type != expected[i] {
+			t.Errorf("Expected event %d to be %s, got %s", i, expected[i], e.Type)
+		}
+		if e.Round != 1 {
+			t.Errorf("Expected event %d on round %d, got %d", i, 1, e.Round)
+		}
+	}
+
+	if a := received[2].Answer; a == nil || !a.Correct {
+		t.Errorf("Expected a correct answer, got %+v", a)
+	}
+
+	if len(received[3].Answers) != 1 {
+		t.Errorf("Expected %d answer on question end, got %d", 1, len(received[3].Answers))
+	}
+
+	if l := received[5].Leaderboard; len(l) != 1 || l[0].Score == 0 {
+		t.Errorf("Expected the final leaderboard, got %+v", l)
+	}
+}
+
+func TestGameWithClient(t *testing.T) {
+	t.Parallel()
+
+	server := opentriviatest.NewServer()
+	defer server.Close()
+
+	g := game.New(server.Client().Question, game.Config{
+		Rounds: []game.Round{{Options: &opentrivia.QuestionListOptions{Limit: 3}}},
+	})
+	defer g.Close()
+
+	if err := g.StartRound(); err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+
+	q, err := g.NextQuestion()
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+
+	if q.Total != 3 {
+		t.Errorf("Expected %d questions, got %d", 3, q.Total)
+	}
+	if len(q.Answers) != len(q.Question.IncorrectAnswers)+1 {
+		t.Errorf("Expected %d answers, got %d", len(q.Question.IncorrectAnswers)+1, len(q.Answers))
+	}
+}
+
+func TestGamePause(t *testing.T) {
+	t.Parallel()
+
+	clock := newFakeClock()
+	g := game.New(newGameSource(), game.Config{TimeLimit: 10 * time.Second, Clock: clock})
+	defer g.Close()
+
+	g.AddPlayer("ana", "Ana")
+	g.StartRound()
+	q, _ := g.NextQuestion()
+
+	g.Pause()
+	clock.Advance(time.Minute)
+
+	t.Run("expect answers to be rejected while paused", func(t *testing.T) {
+		if _, err := g.Answer("ana", "Correct"); err != game.ErrPaused {
+			t.Errorf("Expected %s, got %v", game.ErrPaused, err)
+		}
+	})
+
+	t.Run("expect the deadline to be moved by the pause", func(t *testing.T) {
+		g.Resume()
+
+		current, _ := g.Current()
+		if expected := q.Deadline.Add(time.Minute); !current.Deadline.Equal(expected) {
+			t.Errorf("Expected %s, got %s", expected, current.Deadline)
+		}
+
+		a, err := g.Answer("ana", "Correct")
+		if err != nil {
+			t.Fatalf("Expected no error, got %s", err)
+		}
+		if a.Points != 150 {
+			t.Errorf("Expected %d points, got %d", 150, a.Points)
+		}
+	})
+}
@@ -0,0 +1,187 @@
+package tests
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/pinheirolucas/opentrivia"
+	"github.com/pinheirolucas/opentrivia/opentriviatest"
+)
+
+// hookRecorder records the calls of a RequestHook.
+type hookRecorder struct {
+	mu        sync.Mutex
+	requests  []opentrivia.RequestInfo
+	responses []opentrivia.ResponseInfo
+}
+
+func (h *hookRecorder) BeforeRequest(info opentrivia.RequestInfo) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.requests = append(h.requests, info)
+}
+
+func (h *hookRecorder) AfterResponse(info opentrivia.ResponseInfo) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.responses = append(h.responses, info)
+}
+
+func TestClientHook(t *testing.T) {
+	t.Parallel()
+
+	server := opentriviatest.NewServer()
+	t.Cleanup(server.Close)
+
+	hook := &hookRecorder{}
+	c := server.Client()
+	c.Hook = hook
+
+	token, err := c.Token.Create()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := c.Question.List(&opentrivia.QuestionListOptions{Limit: 7, Token: token}); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("expect a call before and after each request", func(t *testing.T) {
+		if len(hook.requests) != 2 || len(hook.responses) != 2 {
+			t.Fatalf("Expected %d calls, got %d and %d", 2, len(hook.requests), len(hook.responses))
+		}
+	})
+
+	t.Run("expect the token to be redacted", func(t *testing.T) {
+		u := hook.requests[1].URL
+		if strings.Contains(u, string(token)) || !strings.Contains(u, "token=REDACTED") {
+			t.Errorf("Expected the token to be redacted, got %s", u)
+		}
+	})
+
+	t.Run("expect the response to be described", func(t *testing.T) {
+		info := hook.responses[1]
+		if info.StatusCode != 200 {
+			t.Errorf("Expected %d, got %d", 200, info.StatusCode)
+		}
+		if info.ResponseCode != 0 {
+			t.Errorf("Expected %d, got %d", 0, info.ResponseCode)
+		}
+		if info.Results != 7 {
+			t.Errorf("Expected %d, got %d", 7, info.Results)
+		}
+		if info.Duration <= 0 || info.Err != nil {
+			t.Errorf("Expected a duration and no error, got %s and %v", info.Duration, info.Err)
+		}
+	})
+
+	t.Run("expect the API response code", func(t *testing.T) {
+		server.SetResponseCode(opentriviatest.ResponseCodeNoResults)
+		defer server.SetResponseCode(-1)
+
+		if _, err := c.Question.Random(nil); err != opentrivia.ErrNoResults {
+			t.Fatalf("Expected %s, got %v", opentrivia.ErrNoResults, err)
+		}
+
+		info := hook.responses[len(hook.responses)-1]
+		if info.ResponseCode != opentriviatest.ResponseCodeNoResults || info.Results != 0 {
+			t.Errorf("Expected no results, got %d and %d", info.ResponseCode, info.Results)
+		}
+	})
+}
+
+func TestClientHookFuncs(t *testing.T) {
+	t.Parallel()
+
+	c, err := opentrivia.NewClient(opentrivia.WithBaseURL("http://127.0.0.1:1/"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var after opentrivia.ResponseInfo
+	c.Hook = opentrivia.RequestHookFuncs{After: func(info opentrivia.ResponseInfo) { after = info }}
+
+	if _, err := c.Token.Create(); err == nil {
+		t.Fatal("Expected an error")
+	}
+
+	if after.Err == nil || after.StatusCode != 0 || after.ResponseCode != -1 {
+		t.Errorf("Expected the failure to be reported, got %+v", after)
+	}
+}
+
+func TestClientLogger(t *testing.T) {
+	t.Parallel()
+
+	server := opentriviatest.NewServer()
+	t.Cleanup(server.Close)
+
+	var buf bytes.Buffer
+	c := server.Client()
+	c.Logger = slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	token, err := c.Token.Create()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := c.Question.Random(&opentrivia.QuestionRandomOptions{Token: token}); err != nil {
+		t.Fatal(err)
+	}
+
+	out := buf.String()
+
+	if strings.Contains(out, string(token)) {
+		t.Errorf("Expected the token to be redacted, got %s", out)
+	}
+
+	for _, s := range []string{"level=DEBUG", "response_code=0", "results=1", "status=200", "duration="} {
+		if !strings.Contains(out, s) {
+			t.Errorf("Expected %s in the logs, got %s", s, out)
+		}
+	}
+}
+
+func TestClientRedactsTransportErrors(t *testing.T) {
+	t.Parallel()
+
+	c, err := opentrivia.NewClient(opentrivia.WithBaseURL("http://127.0.0.1:1/"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	var after opentrivia.ResponseInfo
+	c.Logger = slog.New(slog.NewTextHandler(&buf, nil))
+	c.Hook = opentrivia.RequestHookFuncs{After: func(info opentrivia.ResponseInfo) { after = info }}
+
+	const token = "secret-token"
+
+	_, err = c.Question.Random(&opentrivia.QuestionRandomOptions{Token: token})
+	if err == nil {
+		t.Fatal("Expected an error")
+	}
+
+	t.Run("expect the returned error to be redacted", func(t *testing.T) {
+		if strings.Contains(err.Error(), token) {
+			t.Errorf("Expected the token to be redacted, got %s", err)
+		}
+	})
+
+	t.Run("expect the hook error to be redacted", func(t *testing.T) {
+		if after.Err == nil || strings.Contains(after.Err.Error(), token) {
+			t.Errorf("Expected the token to be redacted, got %v", after.Err)
+		}
+	})
+
+	t.Run("expect the logged error to be redacted", func(t *testing.T) {
+		if out := buf.String(); strings.Contains(out, token) || !strings.Contains(out, "level=ERROR") {
+			t.Errorf("Expected the token to be redacted, got %s", out)
+		}
+	})
+}
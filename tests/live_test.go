@@ -0,0 +1,556 @@
+package tests
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pinheirolucas/opentrivia/live"
+	"github.com/pinheirolucas/opentrivia/opentriviatest"
+)
+
+// newLiveServer returns a live server backed by the fake Open Trivia API
+// and its URL.
+func newLiveServer(t *testing.T) (*live.Server, string) {
+	upstream := opentriviatest.NewServer()
+	t.Cleanup(upstream.Close)
+
+	s := live.NewServer(upstream.Client())
+	s.PollTimeout = time.Second
+
+	ts := httptest.NewServer(s)
+	t.Cleanup(ts.Close)
+
+	return s, ts.URL
+}
+
+func postForm(t *testing.T, rawurl string, v url.Values, out interface{}) int {
+	resp, err := http.PostForm(rawurl, v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return resp.StatusCode
+}
+
+type liveRoom struct {
+	ID      string `json:"id"`
+	HostKey string `json:"host_key"`
+}
+
+type livePlayer struct {
+	PlayerID string `json:"player_id"`
+	Key      string `json:"key"`
+}
+
+func createLiveRoom(t *testing.T, base string, v url.Values) liveRoom {
+	var room liveRoom
+	if status := postForm(t, base+"/rooms", v, &room); status != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d", http.StatusCreated, status)
+	}
+
+	return room
+}
+
+func joinLiveRoom(t *testing.T, base string, room liveRoom, name string) livePlayer {
+	var p livePlayer
+	if status := postForm(t, base+"/rooms/"+room.ID+"/join", url.Values{"name": {name}}, &p); status != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, status)
+	}
+
+	return p
+}
+
+func dialLive(t *testing.T, base string, room liveRoom, p livePlayer, since int) *live.Conn {
+	v := url.Values{"player_id": {p.PlayerID}, "key": {p.Key}, "since": {strconv.Itoa(since)}}
+
+	conn, err := live.Dial("ws" + strings.TrimPrefix(base, "http") + "/rooms/" + room.ID + "/ws?" + v.Encode())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return conn
+}
+
+// readUntil reads the messages of the connection until one of the
+// provided type.
+func readUntil(t *testing.T, conn *live.Conn, messageType string) live.Message {
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+	for {
+		data, err := conn.ReadMessage()
+		if err != nil {
+			t.Fatalf("Expected a %s message, got %s", messageType, err)
+		}
+
+		var m live.Message
+		if err := json.Unmarshal(data, &m); err != nil {
+			t.Fatal(err)
+		}
+
+		if m.Type == messageType {
+			return m
+		}
+	}
+}
+
+func sendLive(t *testing.T, conn *live.Conn, m live.Message) {
+	data, _ := json.Marshal(m)
+	if err := conn.WriteMessage(data); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLiveWebSocket(t *testing.T) {
+	t.Parallel()
+
+	s, base := newLiveServer(t)
+	room := createLiveRoom(t, base, url.Values{"amount": {"2"}, "type": {"multiple"}, "rounds": {"2"}, "break": {"1ms"}})
+	ana := joinLiveRoom(t, base, room, "Ana")
+	bob := joinLiveRoom(t, base, room, "Bob")
+
+	anaConn := dialLive(t, base, room, ana, 0)
+	bobConn := dialLive(t, base, room, bob, 0)
+
+	host, err := live.Dial("ws" + strings.TrimPrefix(base, "http") + "/rooms/" + room.ID + "/ws?key=" + room.HostKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer host.Close()
+
+	t.Run("expect players to be rejected as host", func(t *testing.T) {
+		sendLive(t, anaConn, live.Message{Type: live.MessageStart})
+
+		if m := readUntil(t, anaConn, live.MessageError); m.Error != live.ErrForbidden.Error() {
+			t.Errorf("Expected %s, got %s", live.ErrForbidden, m.Error)
+		}
+	})
+
+	seen := make(map[string]bool)
+	for round := 1; round <= 2; round++ {
+		sendLive(t, host, live.Message{Type: live.MessageStart})
+
+		for i := 0; i < 2; i++ {
+			q := readUntil(t, anaConn, live.MessageQuestion)
+			readUntil(t, bobConn, live.MessageQuestion)
+
+			if q.Round != round || q.Question.Number != i+1 {
+				t.Errorf("Expected question %d of round %d, got %d of round %d", i+1, round, q.Question.Number, q.Round)
+			}
+			if seen[q.Question.Question] {
+				t.Errorf("Expected the room token to avoid repeating %q", q.Question.Question)
+			}
+			seen[q.Question.Question] = true
+
+			sendLive(t, anaConn, live.Message{Type: live.MessageAnswer, Answer: "Correct"})
+			sendLive(t, bobConn, live.Message{Type: live.MessageAnswer, Answer: "Incorrect 1"})
+
+			if r := readUntil(t, anaConn, live.MessageResult); r.Result == nil || !r.Result.Correct {
+				t.Errorf("Expected a correct result, got %+v", r.Result)
+			}
+
+			ended := readUntil(t, bobConn, live.MessageQuestionEnded)
+			if ended.CorrectAnswer != "Correct" {
+				t.Errorf("Expected the correct answer to be revealed, got %q", ended.CorrectAnswer)
+			}
+			if len(ended.Leaderboard) != 2 || ended.Leaderboard[0].ID != ana.PlayerID {
+				t.Errorf("Expected Ana to lead, got %+v", ended.Leaderboard)
+			}
+		}
+
+		readUntil(t, host, live.MessageRoundEnded)
+	}
+
+	readUntil(t, host, live.MessageGameEnded)
+
+	r, _ := s.Room(room.ID)
+	if r.Token() == "" {
+		t.Error("Expected the room to have a session token")
+	}
+}
+
+// pollLive long polls the messages of the room after since.
+func pollLive(t *testing.T, base string, room liveRoom, p livePlayer, since int) []live.Message {
+	v := url.Values{"player_id": {p.PlayerID}, "key": {p.Key}, "since": {strconv.Itoa(since)}}
+
+	resp, err := http.Get(base + "/rooms/" + room.ID + "/poll?" + v.Encode())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	var messages []live.Message
+	if err := json.NewDecoder(resp.Body).Decode(&messages); err != nil {
+		t.Fatal(err)
+	}
+
+	return messages
+}
+
+// pollUntil long polls the room until a message of the provided type.
+func pollUntil(t *testing.T, base string, room liveRoom, p livePlayer, since *int, messageType string) live.Message {
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		for _, m := range pollLive(t, base, room, p, *since) {
+			*since = m.Seq
+			if m.Type == messageType {
+				return m
+			}
+		}
+	}
+
+	t.Fatalf("Expected a %s message", messageType)
+	return live.Message{}
+}
+
+func TestLiveLongPolling(t *testing.T) {
+	t.Parallel()
+
+	_, base := newLiveServer(t)
+	room := createLiveRoom(t, base, url.Values{"amount": {"1"}, "type": {"multiple"}})
+	ana := joinLiveRoom(t, base, room, "Ana")
+	bob := joinLiveRoom(t, base, room, "Bob")
+	commands := base + "/rooms/" + room.ID + "/"
+
+	t.Run("expect the host controls to require the host key", func(t *testing.T) {
+		if status := postForm(t, commands+"start", url.Values{"key": {ana.Key}}, nil); status != http.StatusForbidden {
+			t.Errorf("Expected status %d, got %d", http.StatusForbidden, status)
+		}
+	})
+
+	t.Run("expect answers to require the player key", func(t *testing.T) {
+		v := url.Values{"player_id": {ana.PlayerID}, "key": {bob.Key}, "answer": {"Correct"}}
+		if status := postForm(t, commands+"answer", v, nil); status != http.StatusForbidden {
+			t.Errorf("Expected status %d, got %d", http.StatusForbidden, status)
+		}
+	})
+
+	if status := postForm(t, commands+"start", url.Values{"key": {room.HostKey}}, nil); status != http.StatusNoContent {
+		t.Fatalf("Expected status %d, got %d", http.StatusNoContent, status)
+	}
+
+	var since int
+	q := pollUntil(t, base, room, ana, &since, live.MessageQuestion)
+	if q.CorrectAnswer != "" {
+		t.Error("Expected the correct answer to be hidden")
+	}
+
+	v := url.Values{"player_id": {ana.PlayerID}, "key": {ana.Key}, "answer": {"Correct"}}
+	if status := postForm(t, commands+"answer", v, nil); status != http.StatusNoContent {
+		t.Fatalf("Expected status %d, got %d", http.StatusNoContent, status)
+	}
+
+	t.Run("expect a second answer to conflict", func(t *testing.T) {
+		if status := postForm(t, commands+"answer", v, nil); status != http.StatusConflict {
+			t.Errorf("Expected status %d, got %d", http.StatusConflict, status)
+		}
+	})
+
+	t.Run("expect the results to be private", func(t *testing.T) {
+		var bobSince int
+		for _, m := range pollLive(t, base, room, bob, 0) {
+			bobSince = m.Seq
+			if m.Type == live.MessageResult {
+				t.Errorf("Expected Bob not to receive the result of %s", m.PlayerID)
+			}
+		}
+
+		if bobSince < since {
+			t.Errorf("Expected Bob to receive the messages up to %d, got %d", since, bobSince)
+		}
+
+		if r := pollUntil(t, base, room, ana, &since, live.MessageResult); !r.Result.Correct {
+			t.Errorf("Expected a correct result, got %+v", r.Result)
+		}
+	})
+
+	t.Run("expect the host to skip the question", func(t *testing.T) {
+		if status := postForm(t, commands+"skip", url.Values{"key": {room.HostKey}}, nil); status != http.StatusNoContent {
+			t.Fatalf("Expected status %d, got %d", http.StatusNoContent, status)
+		}
+
+		m := pollUntil(t, base, room, bob, &since, live.MessageGameEnded)
+		if len(m.Leaderboard) != 2 || m.Leaderboard[0].ID != ana.PlayerID {
+			t.Errorf("Expected Ana to win, got %+v", m.Leaderboard)
+		}
+	})
+}
+
+func TestLivePause(t *testing.T) {
+	t.Parallel()
+
+	_, base := newLiveServer(t)
+	room := createLiveRoom(t, base, url.Values{"amount": {"1"}, "type": {"multiple"}, "time_limit": {"200ms"}})
+	ana := joinLiveRoom(t, base, room, "Ana")
+	commands := base + "/rooms/" + room.ID + "/"
+	host := url.Values{"key": {room.HostKey}}
+
+	postForm(t, commands+"start", host, nil)
+
+	var since int
+	pollUntil(t, base, room, ana, &since, live.MessageQuestion)
+
+	if status := postForm(t, commands+"pause", host, nil); status != http.StatusNoContent {
+		t.Fatalf("Expected status %d, got %d", http.StatusNoContent, status)
+	}
+	pollUntil(t, base, room, ana, &since, live.MessagePaused)
+
+	// The question would have timed out if the pause did not stop the
+	// clock.
+	time.Sleep(300 * time.Millisecond)
+
+	v := url.Values{"player_id": {ana.PlayerID}, "key": {ana.Key}, "answer": {"Correct"}}
+	if status := postForm(t, commands+"answer", v, nil); status != http.StatusConflict {
+		t.Errorf("Expected answers to be rejected while paused, got status %d", status)
+	}
+
+	postForm(t, commands+"resume", host, nil)
+
+	m := pollUntil(t, base, room, ana, &since, live.MessageResumed)
+	if !m.Question.Deadline.After(time.Now()) {
+		t.Errorf("Expected the deadline to be moved, got %s", m.Question.Deadline)
+	}
+
+	if status := postForm(t, commands+"answer", v, nil); status != http.StatusNoContent {
+		t.Errorf("Expected status %d, got %d", http.StatusNoContent, status)
+	}
+}
+
+func TestLiveServerSentEvents(t *testing.T) {
+	t.Parallel()
+
+	_, base := newLiveServer(t)
+	room := createLiveRoom(t, base, nil)
+	ana := joinLiveRoom(t, base, room, "Ana")
+	v := url.Values{"player_id": {ana.PlayerID}, "key": {ana.Key}}
+
+	// read returns the id and the message of the next event of the stream.
+	read := func(r *bufio.Reader) (string, live.Message) {
+		var (
+			id string
+			m  live.Message
+		)
+
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			line = strings.TrimSpace(line)
+			switch {
+			case strings.HasPrefix(line, "id: "):
+				id = strings.TrimPrefix(line, "id: ")
+			case strings.HasPrefix(line, "data: "):
+				json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &m)
+			case line == "" && id != "":
+				return id, m
+			}
+		}
+	}
+
+	open := func(lastEventID string) *http.Response {
+		req, _ := http.NewRequest(http.MethodGet, base+"/rooms/"+room.ID+"/events?"+v.Encode(), nil)
+		if lastEventID != "" {
+			req.Header.Set("Last-Event-ID", lastEventID)
+		}
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return resp
+	}
+
+	resp := open("")
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("Expected %s, got %s", "text/event-stream", ct)
+	}
+
+	id, m := read(bufio.NewReader(resp.Body))
+	if id != "1" || m.Type != live.MessagePlayerJoined {
+		t.Errorf("Expected the join as event 1, got %s as event %s", m.Type, id)
+	}
+	resp.Body.Close()
+
+	t.Run("expect the stream to resume after the last event", func(t *testing.T) {
+		resp := open(id)
+		defer resp.Body.Close()
+
+		id, m := read(bufio.NewReader(resp.Body))
+		if id == "1" || m.Seq <= 1 {
+			t.Errorf("Expected an event after 1, got %s as event %s", m.Type, id)
+		}
+	})
+}
+
+func TestLiveReconnect(t *testing.T) {
+	t.Parallel()
+
+	_, base := newLiveServer(t)
+	room := createLiveRoom(t, base, url.Values{"amount": {"1"}, "type": {"multiple"}})
+	ana := joinLiveRoom(t, base, room, "Ana")
+	bob := joinLiveRoom(t, base, room, "Bob")
+
+	anaConn := dialLive(t, base, room, ana, 0)
+	bobConn := dialLive(t, base, room, bob, 0)
+
+	m := readUntil(t, bobConn, live.MessagePlayerConnected)
+	last := readUntil(t, anaConn, live.MessagePlayerConnected).Seq
+	anaConn.Close()
+
+	if m := readUntil(t, bobConn, live.MessagePlayerDisconnected); m.PlayerID != ana.PlayerID {
+		t.Errorf("Expected Ana to disconnect, got %s", m.PlayerID)
+	}
+
+	postForm(t, base+"/rooms/"+room.ID+"/start", url.Values{"key": {room.HostKey}}, nil)
+	readUntil(t, bobConn, live.MessageQuestion)
+
+	anaConn = dialLive(t, base, room, ana, last)
+
+	t.Run("expect the missed messages after reconnecting", func(t *testing.T) {
+		q := readUntil(t, anaConn, live.MessageQuestion)
+		if q.Seq <= last || q.Seq <= m.Seq {
+			t.Errorf("Expected a message after %d, got %d", last, q.Seq)
+		}
+	})
+
+	t.Run("expect the player to keep playing", func(t *testing.T) {
+		sendLive(t, anaConn, live.Message{Type: live.MessageAnswer, Answer: "Correct"})
+
+		if r := readUntil(t, anaConn, live.MessageResult); !r.Result.Correct {
+			t.Errorf("Expected a correct result, got %+v", r.Result)
+		}
+	})
+}
+
+func TestLiveRoomExpiry(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu  sync.Mutex
+		now = time.Now()
+	)
+	advance := func(d time.Duration) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		now = now.Add(d)
+	}
+
+	s, base := newLiveServer(t)
+	s.IdleTimeout = time.Hour
+	s.FinishedTimeout = 5 * time.Minute
+	s.Now = func() time.Time {
+		mu.Lock()
+		defer mu.Unlock()
+
+		return now
+	}
+
+	idle := createLiveRoom(t, base, url.Values{"amount": {"1"}})
+
+	finished := s.CreateRoom(live.RoomConfig{})
+	finished.Close()
+
+	// Any request removes the expired rooms.
+	sweep := func() {
+		resp, err := http.Get(base + "/rooms/none/poll")
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+
+	advance(10 * time.Minute)
+	sweep()
+
+	t.Run("expect a finished room to be removed", func(t *testing.T) {
+		if _, ok := s.Room(finished.ID); ok {
+			t.Error("Expected the finished room to be removed")
+		}
+		if _, ok := s.Room(idle.ID); !ok {
+			t.Error("Expected the idle room to be kept")
+		}
+	})
+
+	advance(time.Hour)
+	sweep()
+
+	t.Run("expect an idle room to be removed", func(t *testing.T) {
+		if _, ok := s.Room(idle.ID); ok {
+			t.Error("Expected the idle room to be removed")
+		}
+	})
+}
+
+func TestLiveWebSocketOrigin(t *testing.T) {
+	t.Parallel()
+
+	handshake := func(base, origin string) int {
+		room := createLiveRoom(t, base, url.Values{"amount": {"1"}})
+
+		req, err := http.NewRequest(http.MethodGet, base+"/rooms/"+room.ID+"/ws?key="+room.HostKey, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Connection", "Upgrade")
+		req.Header.Set("Upgrade", "websocket")
+		req.Header.Set("Sec-WebSocket-Version", "13")
+		req.Header.Set("Sec-WebSocket-Key", "dGhlIHNhbXBsZSBub25jZQ==")
+		req.Header.Set("Origin", origin)
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+
+		return resp.StatusCode
+	}
+
+	_, base := newLiveServer(t)
+
+	t.Run("expect the same origin to be accepted", func(t *testing.T) {
+		if status := handshake(base, base); status != http.StatusSwitchingProtocols {
+			t.Errorf("Expected status %d, got %d", http.StatusSwitchingProtocols, status)
+		}
+	})
+
+	t.Run("expect another origin to be rejected", func(t *testing.T) {
+		if status := handshake(base, "https://evil.example"); status != http.StatusForbidden {
+			t.Errorf("Expected status %d, got %d", http.StatusForbidden, status)
+		}
+	})
+
+	t.Run("expect CheckOrigin to allow other origins", func(t *testing.T) {
+		upstream := opentriviatest.NewServer()
+		t.Cleanup(upstream.Close)
+
+		s := live.NewServer(upstream.Client())
+		s.CheckOrigin = func(r *http.Request) bool { return r.Header.Get("Origin") == "https://quiz.example" }
+
+		ts := httptest.NewServer(s)
+		t.Cleanup(ts.Close)
+
+		if status := handshake(ts.URL, "https://quiz.example"); status != http.StatusSwitchingProtocols {
+			t.Errorf("Expected status %d, got %d", http.StatusSwitchingProtocols, status)
+		}
+	})
+}
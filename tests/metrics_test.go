@@ -0,0 +1,153 @@
+package tests
+
+import (
+	"expvar"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/pinheirolucas/opentrivia"
+	"github.com/pinheirolucas/opentrivia/opentriviatest"
+	"github.com/pinheirolucas/opentrivia/server"
+)
+
+// counterValue returns the value of a counter of m, or 0 if it was never
+// emitted.
+func counterValue(m *opentrivia.ExpvarMetrics, name string, labels opentrivia.Labels) float64 {
+	v, ok := m.Get(name, labels).(*expvar.Float)
+	if !ok {
+		return 0
+	}
+
+	return v.Value()
+}
+
+// publishedMetrics is published once, as expvar names can not be reused
+// when the tests run more than once.
+var (
+	publishOnce      sync.Once
+	publishedMetrics *opentrivia.ExpvarMetrics
+)
+
+func TestExpvarMetrics(t *testing.T) {
+	t.Parallel()
+
+	t.Run("expect the metrics to be published", func(t *testing.T) {
+		publishOnce.Do(func() {
+			publishedMetrics = opentrivia.NewExpvarMetrics("opentrivia_tests")
+		})
+
+		if expvar.Get("opentrivia_tests") != publishedMetrics {
+			t.Error("Expected the metrics to be published")
+		}
+	})
+
+	m := opentrivia.NewExpvarMetrics("")
+
+	t.Run("expect the counters to add up", func(t *testing.T) {
+		m.Add("counter", 1, opentrivia.Labels{"b": "2", "a": "1"})
+		m.Add("counter", 2, opentrivia.Labels{"a": "1", "b": "2"})
+
+		if v := counterValue(m, "counter", opentrivia.Labels{"a": "1", "b": "2"}); v != 3 {
+			t.Errorf("Expected %d, got %g", 3, v)
+		}
+	})
+
+	t.Run("expect the histograms to count the buckets", func(t *testing.T) {
+		for _, v := range []float64{0.2, 0.7, 20} {
+			m.Observe("histogram", v, nil)
+		}
+
+		h := m.Get("histogram", nil).(*expvar.Map)
+		expected := map[string]string{"count": "3", "sum": "20.9", "le_0.25": "1", "le_1": "2", "le_10": "2"}
+		for key, value := range expected {
+			if v := h.Get(key); v == nil || v.String() != value {
+				t.Errorf("Expected %s to be %s, got %v", key, value, v)
+			}
+		}
+	})
+}
+
+func TestClientMetrics(t *testing.T) {
+	t.Parallel()
+
+	fake := opentriviatest.NewServer()
+	t.Cleanup(fake.Close)
+
+	fake.SetQuestions([]opentrivia.Question{
+		opentriviatest.MultipleQuestion(opentrivia.QuestionCategoryComputer, opentrivia.QuestionDifficultyEasy, 1),
+		opentriviatest.MultipleQuestion(opentrivia.QuestionCategoryComputer, opentrivia.QuestionDifficultyEasy, 2),
+	})
+
+	m := opentrivia.NewExpvarMetrics("")
+	c := fake.Client()
+	c.Metrics = m
+
+	token, err := c.Token.Create()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	options := &opentrivia.QuestionListOptions{Limit: 2, Token: token, AutoRefresh: true}
+	for i := 0; i < 2; i++ {
+		if _, err := c.Question.List(options); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cases := []struct {
+		name     string
+		labels   opentrivia.Labels
+		expected float64
+	}{
+		{opentrivia.MetricRequests, opentrivia.Labels{"endpoint": "api.php", "response_code": "0"}, 2},
+		{opentrivia.MetricRequests, opentrivia.Labels{"endpoint": "api.php", "response_code": "4"}, 1},
+		{opentrivia.MetricRequests, opentrivia.Labels{"endpoint": "api_token.php", "response_code": "0"}, 2},
+		{opentrivia.MetricTokenCreates, nil, 1},
+		{opentrivia.MetricTokenRefreshes, opentrivia.Labels{"auto": "true"}, 1},
+		{opentrivia.MetricRetries, opentrivia.Labels{"endpoint": "api.php", "reason": "token_empty"}, 1},
+	}
+
+	for _, tc := range cases {
+		if v := counterValue(m, tc.name, tc.labels); v != tc.expected {
+			t.Errorf("Expected %s%v to be %g, got %g", tc.name, tc.labels, tc.expected, v)
+		}
+	}
+
+	h, ok := m.Get(opentrivia.MetricRequestDuration, opentrivia.Labels{"endpoint": "api.php"}).(*expvar.Map)
+	if !ok || h.Get("count").String() != "3" {
+		t.Errorf("Expected %d latencies, got %v", 3, h)
+	}
+}
+
+func TestProxyMetrics(t *testing.T) {
+	t.Parallel()
+
+	upstream := opentriviatest.NewServer()
+	t.Cleanup(upstream.Close)
+
+	m := opentrivia.NewExpvarMetrics("")
+	uc := upstream.Client()
+	uc.Metrics = m
+
+	proxy := httptest.NewServer(server.NewProxy(uc, 0))
+	t.Cleanup(proxy.Close)
+
+	c, err := opentrivia.NewClient(opentrivia.WithBaseURL(proxy.URL + "/"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 3; i++ {
+		if _, err := c.Question.List(&opentrivia.QuestionListOptions{Limit: 5}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if v := counterValue(m, opentrivia.MetricCacheHits, opentrivia.Labels{"cache": "questions"}); v != 2 {
+		t.Errorf("Expected %d cache hits, got %g", 2, v)
+	}
+	if v := counterValue(m, opentrivia.MetricCacheMisses, opentrivia.Labels{"cache": "questions"}); v != 1 {
+		t.Errorf("Expected %d cache miss, got %g", 1, v)
+	}
+}
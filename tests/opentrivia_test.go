@@ -1,16 +1,31 @@
 package tests
 
 import (
+	"net"
+	"net/http"
+	"net/http/httptest"
 	"net/url"
+	"os"
+	"sync/atomic"
 	"testing"
 
 	"github.com/pinheirolucas/opentrivia"
+	"github.com/pinheirolucas/opentrivia/opentriviatest"
+	"github.com/pinheirolucas/opentrivia/server"
 )
 
+// client is pointed at a fake Open Trivia API, so the tests can run
+// offline.
 var client *opentrivia.Client
 
-func init() {
-	client = opentrivia.DefaultClient
+func TestMain(m *testing.M) {
+	server := opentriviatest.NewServer()
+	client = server.Client()
+
+	code := m.Run()
+
+	server.Close()
+	os.Exit(code)
 }
 
 func TestClientNewRequest(t *testing.T) {
@@ -19,7 +34,7 @@ func TestClientNewRequest(t *testing.T) {
 	t.Run("should not return an error", func(t *testing.T) {
 		t.Parallel()
 
-		_, err := client.NewRequest("api.php", make(url.Values))
+		_, err := opentrivia.DefaultClient.NewRequest("api.php", make(url.Values))
 
 		if err != nil {
 			t.Errorf("No errors expected, got: %s", err)
@@ -34,7 +49,7 @@ func TestClientNewRequest(t *testing.T) {
 		v := make(url.Values)
 		v.Set("command", "request")
 
-		req, _ := client.NewRequest("api.php", v)
+		req, _ := opentrivia.DefaultClient.NewRequest("api.php", v)
 		URL := req.URL.String()
 
 		if URL != expectedURL {
@@ -49,7 +64,7 @@ func TestClientNewRequest(t *testing.T) {
 
 		v := make(url.Values)
 
-		req, _ := client.NewRequest("api.php", v)
+		req, _ := opentrivia.DefaultClient.NewRequest("api.php", v)
 		method := req.Method
 
 		if method != expectedMethod {
@@ -57,3 +72,95 @@ func TestClientNewRequest(t *testing.T) {
 		}
 	})
 }
+
+// newConnCountingServer returns a fake Open Trivia API counting the
+// connections it accepts.
+func newConnCountingServer(t testing.TB) (*httptest.Server, *int64) {
+	var conns int64
+
+	ts := httptest.NewUnstartedServer(server.NewServer(newMemoryStore(t, opentriviatest.Questions())))
+	ts.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			atomic.AddInt64(&conns, 1)
+		}
+	}
+	ts.Start()
+	t.Cleanup(ts.Close)
+
+	return ts, &conns
+}
+
+func TestClientConnections(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name     string
+		options  []opentrivia.Option
+		expected int64
+	}{
+		{"expect the connection to be reused by default", nil, 1},
+		{"expect a connection per request when closing them", []opentrivia.Option{opentrivia.WithCloseConnections()}, 5},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ts, conns := newConnCountingServer(t)
+
+			c, err := opentrivia.NewClient(append(tc.options, opentrivia.WithBaseURL(ts.URL+"/"))...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for i := 0; i < 5; i++ {
+				if _, err := c.Question.Random(nil); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if n := atomic.LoadInt64(conns); n != tc.expected {
+				t.Errorf("Expected %d connections, got %d", tc.expected, n)
+			}
+		})
+	}
+}
+
+// BenchmarkClientConnections compares the keep-alive connections to a
+// connection per request, against a fake Open Trivia API served over TLS
+// like the real one.
+func BenchmarkClientConnections(b *testing.B) {
+	cases := []struct {
+		name    string
+		options []opentrivia.Option
+	}{
+		{"keep-alive", nil},
+		{"close", []opentrivia.Option{opentrivia.WithCloseConnections()}},
+	}
+
+	for _, bc := range cases {
+		b.Run(bc.name, func(b *testing.B) {
+			store := newMemoryStore(b, []opentrivia.Question{
+				opentriviatest.MultipleQuestion(opentrivia.QuestionCategoryComputer, opentrivia.QuestionDifficultyEasy, 1),
+			})
+
+			ts := httptest.NewTLSServer(server.NewServer(store))
+			defer ts.Close()
+
+			options := append(bc.options, opentrivia.WithBaseURL(ts.URL+"/"), opentrivia.WithHTTPClient(ts.Client()))
+			c, err := opentrivia.NewClient(options...)
+			if err != nil {
+				b.Fatal(err)
+			}
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := c.Question.Random(nil); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
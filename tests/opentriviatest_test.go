@@ -0,0 +1,102 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pinheirolucas/opentrivia"
+	"github.com/pinheirolucas/opentrivia/opentriviatest"
+)
+
+func TestFakeServerTokens(t *testing.T) {
+	t.Parallel()
+
+	server := opentriviatest.NewServer()
+	defer server.Close()
+
+	c := server.Client()
+	server.SetQuestions([]opentrivia.Question{
+		opentriviatest.MultipleQuestion(opentrivia.QuestionCategoryArt, opentrivia.QuestionDifficultyEasy, 0),
+		opentriviatest.MultipleQuestion(opentrivia.QuestionCategoryArt, opentrivia.QuestionDifficultyEasy, 1),
+	})
+
+	token, err := c.Token.Create()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	options := &opentrivia.QuestionListOptions{Limit: 2, Token: token}
+	if _, err := c.Question.List(options); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := c.Question.List(options); err != opentrivia.ErrTokenEmpty {
+		t.Errorf("Expected opentrivia.ErrTokenEmpty, got %v", err)
+	}
+
+	options.AutoRefresh = true
+	if _, err := c.Question.List(options); err != nil {
+		t.Errorf("Expected the token to be refreshed, got %v", err)
+	}
+}
+
+func TestFakeServerResponseCode(t *testing.T) {
+	t.Parallel()
+
+	server := opentriviatest.NewServer()
+	defer server.Close()
+
+	c := server.Client()
+	server.SetResponseCode(opentriviatest.ResponseCodeTokenNotFound)
+
+	if _, err := c.Question.List(nil); err != opentrivia.ErrTokenNotFound {
+		t.Errorf("Expected opentrivia.ErrTokenNotFound, got %v", err)
+	}
+
+	server.SetResponseCode(-1)
+
+	if _, err := c.Question.List(nil); err != nil {
+		t.Errorf("No errors expected, got: %s", err)
+	}
+}
+
+func TestFakeServerRateLimit(t *testing.T) {
+	t.Parallel()
+
+	server := opentriviatest.NewServer()
+	defer server.Close()
+
+	c := server.Client()
+	server.SetRateLimit(1, time.Hour)
+
+	if _, err := c.Token.Create(); err != nil {
+		t.Fatal(err)
+	}
+
+	if token, _ := c.Token.Create(); token != "" {
+		t.Errorf("Expected the second request to be rate limited, got token %s", token)
+	}
+
+	if requests := server.Requests(); requests != 2 {
+		t.Errorf("Expected 2 requests, got %d", requests)
+	}
+}
+
+func TestFakeServerLatency(t *testing.T) {
+	t.Parallel()
+
+	server := opentriviatest.NewServer()
+	defer server.Close()
+
+	const latency = 50 * time.Millisecond
+	server.SetLatency(latency)
+
+	start := time.Now()
+	if _, err := server.Client().Category.List(); err != nil {
+		t.Fatal(err)
+	}
+
+	if elapsed := time.Since(start); elapsed < latency {
+		t.Errorf("Expected the response to take at least %s, got %s", latency, elapsed)
+	}
+}
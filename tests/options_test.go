@@ -0,0 +1,146 @@
+package tests
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/pinheirolucas/opentrivia"
+	"github.com/pinheirolucas/opentrivia/opentriviatest"
+)
+
+func TestNewClientOptions(t *testing.T) {
+	t.Parallel()
+
+	t.Run("expect the defaults without options", func(t *testing.T) {
+		c, err := opentrivia.NewClient()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if c.BaseURL.String() != "https://opentdb.com/" {
+			t.Errorf("Expected %s, got %s", "https://opentdb.com/", c.BaseURL)
+		}
+	})
+
+	t.Run("expect invalid values to be rejected", func(t *testing.T) {
+		invalid := map[string]opentrivia.Option{
+			"base URL without trailing slash": opentrivia.WithBaseURL("https://opentdb.com"),
+			"relative base URL":               opentrivia.WithBaseURL("/api/"),
+			"nil HTTP client":                 opentrivia.WithHTTPClient(nil),
+			"empty user agent":                opentrivia.WithUserAgent(" "),
+			"invalid list options":            opentrivia.WithDefaultListOptions(opentrivia.QuestionListOptions{Limit: 60}),
+			"invalid random options":          opentrivia.WithDefaultRandomOptions(opentrivia.QuestionRandomOptions{Type: "open"}),
+			"negative rate limit":             opentrivia.WithRateLimit(-time.Second),
+		}
+
+		for name, opt := range invalid {
+			if c, err := opentrivia.NewClient(opt); err == nil || c != nil {
+				t.Errorf("Expected an error for the %s, got %v", name, err)
+			}
+		}
+	})
+
+	t.Run("expect the HTTP client and the user agent to be used", func(t *testing.T) {
+		httpClient := &http.Client{Timeout: time.Second}
+
+		c, err := opentrivia.NewClient(
+			opentrivia.WithHTTPClient(httpClient),
+			opentrivia.WithUserAgent("quiz-night/1.0"),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		req, err := c.NewRequest("api.php", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if ua := req.Header.Get("User-Agent"); ua != "quiz-night/1.0" {
+			t.Errorf("Expected %s, got %s", "quiz-night/1.0", ua)
+		}
+	})
+}
+
+func TestClientDefaultOptions(t *testing.T) {
+	t.Parallel()
+
+	fake := opentriviatest.NewServer()
+	t.Cleanup(fake.Close)
+
+	c, err := opentrivia.NewClient(
+		opentrivia.WithBaseURL(fake.URL+"/"),
+		opentrivia.WithDefaultListOptions(opentrivia.QuestionListOptions{
+			Category: opentrivia.QuestionCategoryHistory,
+			Limit:    3,
+		}),
+		opentrivia.WithDefaultRandomOptions(opentrivia.QuestionRandomOptions{
+			Difficulty: opentrivia.QuestionDifficultyHard,
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("expect the default list options", func(t *testing.T) {
+		questions, err := c.Question.List(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(questions) != 3 || questions[0].Category != "History" {
+			t.Errorf("Expected %d history questions, got %v", 3, questions)
+		}
+	})
+
+	t.Run("expect the default limit", func(t *testing.T) {
+		questions, err := c.Question.List(&opentrivia.QuestionListOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(questions) != 3 {
+			t.Errorf("Expected %d questions, got %d", 3, len(questions))
+		}
+	})
+
+	t.Run("expect the default random options", func(t *testing.T) {
+		q, err := c.Question.Random(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if q.Difficulty != "hard" {
+			t.Errorf("Expected %s, got %s", "hard", q.Difficulty)
+		}
+	})
+}
+
+func TestClientRateLimit(t *testing.T) {
+	t.Parallel()
+
+	fake := opentriviatest.NewServer()
+	t.Cleanup(fake.Close)
+
+	const interval = 50 * time.Millisecond
+
+	c, err := opentrivia.NewClient(
+		opentrivia.WithBaseURL(fake.URL+"/"),
+		opentrivia.WithRateLimit(interval),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if _, err := c.Question.Random(nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if elapsed := time.Since(start); elapsed < 2*interval {
+		t.Errorf("Expected the requests to be spaced by %s, got %s in total", interval, elapsed)
+	}
+}
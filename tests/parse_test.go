@@ -0,0 +1,178 @@
+package tests
+
+import (
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/google/go-querystring/query"
+	"github.com/pinheirolucas/opentrivia"
+)
+
+func TestParseQuestionCategory(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]opentrivia.QuestionCategory{
+		"18":                         opentrivia.QuestionCategoryComputer,
+		"computers":                  opentrivia.QuestionCategoryComputer,
+		"Science: Computers":         opentrivia.QuestionCategoryComputer,
+		"entertainment: video games": opentrivia.QuestionCategoryVideoGame,
+		"TV":                         opentrivia.QuestionCategoryTelevision,
+		"200":                        200,
+	}
+
+	for s, expected := range cases {
+		result, err := opentrivia.ParseQuestionCategory(s)
+		if err != nil {
+			t.Errorf("No errors expected for %q, got: %s", s, err)
+		}
+		if result != expected {
+			t.Errorf("Expected %d for %q, got %d", expected, s, result)
+		}
+	}
+
+	if _, err := opentrivia.ParseQuestionCategory("cooking"); err == nil {
+		t.Error("Expected an error for an unknown category")
+	}
+}
+
+func TestParseQuestionDifficulty(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]opentrivia.QuestionDifficulty{
+		"easy":   opentrivia.QuestionDifficultyEasy,
+		"Medium": opentrivia.QuestionDifficultyMedium,
+		"h":      opentrivia.QuestionDifficultyHard,
+	}
+
+	for s, expected := range cases {
+		result, err := opentrivia.ParseQuestionDifficulty(s)
+		if err != nil {
+			t.Errorf("No errors expected for %q, got: %s", s, err)
+		}
+		if result != expected {
+			t.Errorf("Expected %s for %q, got %s", expected, s, result)
+		}
+	}
+
+	if _, err := opentrivia.ParseQuestionDifficulty("hrad"); err == nil {
+		t.Error("Expected an error for a misspelled difficulty")
+	}
+}
+
+func TestParseQuestionType(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]opentrivia.QuestionType{
+		"multiple":   opentrivia.QuestionTypeMultiple,
+		"true/false": opentrivia.QuestionTypeTrueFalse,
+		"boolean":    opentrivia.QuestionTypeTrueFalse,
+	}
+
+	for s, expected := range cases {
+		result, err := opentrivia.ParseQuestionType(s)
+		if err != nil {
+			t.Errorf("No errors expected for %q, got: %s", s, err)
+		}
+		if result != expected {
+			t.Errorf("Expected %s for %q, got %s", expected, s, result)
+		}
+	}
+}
+
+func TestParseQuestionListOptions(t *testing.T) {
+	t.Parallel()
+
+	t.Run("expect the user friendly values to be parsed", func(t *testing.T) {
+		t.Parallel()
+
+		v, _ := url.ParseQuery("difficulty=hard&category=computers&type=multiple")
+
+		options, err := opentrivia.ParseQuestionListOptions(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		expected := &opentrivia.QuestionListOptions{
+			Category:   opentrivia.QuestionCategoryComputer,
+			Difficulty: opentrivia.QuestionDifficultyHard,
+			Type:       opentrivia.QuestionTypeMultiple,
+		}
+
+		if !reflect.DeepEqual(options, expected) {
+			t.Errorf("Expected %+v, got %+v", expected, options)
+		}
+	})
+
+	t.Run("expect to round-trip with the encoded options", func(t *testing.T) {
+		t.Parallel()
+
+		expected := &opentrivia.QuestionListOptions{
+			Category:   opentrivia.QuestionCategoryVideoGame,
+			Difficulty: opentrivia.QuestionDifficultyEasy,
+			Limit:      20,
+			Token:      "token",
+			Type:       opentrivia.QuestionTypeTrueFalse,
+		}
+
+		v, err := query.Values(expected)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		options, err := opentrivia.ParseQuestionListOptions(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !reflect.DeepEqual(options, expected) {
+			t.Errorf("Expected %+v, got %+v", expected, options)
+		}
+	})
+
+	t.Run("expect an error for each invalid value", func(t *testing.T) {
+		t.Parallel()
+
+		v, _ := url.ParseQuery("difficulty=hrad&amount=many")
+
+		_, err := opentrivia.ParseQuestionListOptions(v)
+
+		verr, ok := err.(*opentrivia.ValidationError)
+		if !ok {
+			t.Fatalf("Expected *opentrivia.ValidationError, got %T", err)
+		}
+
+		if len(verr.Errors) != 2 {
+			t.Errorf("Expected 2 field errors, got: %s", err)
+		}
+	})
+
+	t.Run("expect an amount out of range to be reported as such", func(t *testing.T) {
+		t.Parallel()
+
+		for _, amount := range []string{"0", "51", "300"} {
+			v := url.Values{"amount": {amount}}
+
+			_, err := opentrivia.ParseQuestionListOptions(v)
+			if err == nil || !strings.Contains(err.Error(), "must be between 1 and 50") {
+				t.Errorf("Expected the amount %s to be out of range, got %v", amount, err)
+			}
+		}
+	})
+}
+
+func TestParseQuestionRandomOptions(t *testing.T) {
+	t.Parallel()
+
+	v, _ := url.ParseQuery("category=tv&difficulty=e")
+
+	options, err := opentrivia.ParseQuestionRandomOptions(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if options.Category != opentrivia.QuestionCategoryTelevision || options.Difficulty != opentrivia.QuestionDifficultyEasy {
+		t.Errorf("Unexpected options %+v", options)
+	}
+}
@@ -0,0 +1,258 @@
+package tests
+
+import (
+	"context"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pinheirolucas/opentrivia"
+	"github.com/pinheirolucas/opentrivia/opentriviatest"
+	"github.com/pinheirolucas/opentrivia/prefetch"
+	"github.com/pinheirolucas/opentrivia/server"
+)
+
+func newPrefetcher(t *testing.T, size int) (*prefetch.Prefetcher, *opentriviatest.Server) {
+	fake := opentriviatest.NewServer()
+	t.Cleanup(fake.Close)
+
+	p := prefetch.New(fake.Client(), size)
+	t.Cleanup(func() { p.Close() })
+
+	return p, fake
+}
+
+// waitReady waits until the pool of the key holds n questions.
+func waitReady(t *testing.T, p *prefetch.Prefetcher, key prefetch.Key, n int) {
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if l := p.Levels()[key]; l.Ready == n && !l.Refilling {
+			return
+		}
+
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	t.Fatalf("Expected %d questions ready, got %+v", n, p.Levels()[key])
+}
+
+func TestPrefetcherWarm(t *testing.T) {
+	t.Parallel()
+
+	p, fake := newPrefetcher(t, 5)
+	key := prefetch.Key{Category: opentrivia.QuestionCategoryHistory, Difficulty: opentrivia.QuestionDifficultyHard}
+
+	if err := p.Warm(key); err != nil {
+		t.Fatal(err)
+	}
+	waitReady(t, p, key, 5)
+
+	// The questions are served from the pool, even if upstream is slow.
+	fake.SetLatency(time.Second)
+	defer fake.SetLatency(0)
+
+	start := time.Now()
+	q, err := p.Random(&opentrivia.QuestionRandomOptions{
+		Category:   opentrivia.QuestionCategoryHistory,
+		Difficulty: opentrivia.QuestionDifficultyHard,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Expected the question to be served instantly, got %s", elapsed)
+	}
+	if q.Category != "History" || q.Difficulty != "hard" {
+		t.Errorf("Expected a hard history question, got %s %s", q.Difficulty, q.Category)
+	}
+
+	if l := p.Levels()[key]; l.Ready != 4 || l.Size != 5 || !l.Refilling {
+		t.Errorf("Expected the pool to be refilling, got %+v", l)
+	}
+}
+
+func TestPrefetcherNoRepeats(t *testing.T) {
+	t.Parallel()
+
+	p, _ := newPrefetcher(t, 3)
+	key := prefetch.Key{Category: opentrivia.QuestionCategoryArt, Type: opentrivia.QuestionTypeMultiple}
+
+	seen := make(map[string]bool)
+	for i := 0; i < 10; i++ {
+		q, err := p.Next(context.Background(), key)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if seen[q.Question] {
+			t.Errorf("Expected no repeated question, got %q twice", q.Question)
+		}
+		seen[q.Question] = true
+	}
+
+	waitReady(t, p, key, 3)
+}
+
+func TestPrefetcherErrors(t *testing.T) {
+	t.Parallel()
+
+	t.Run("expect the refill error", func(t *testing.T) {
+		t.Parallel()
+
+		p, fake := newPrefetcher(t, 3)
+		fake.SetResponseCode(opentriviatest.ResponseCodeRateLimit)
+
+		if _, err := p.Random(nil); err != opentrivia.ErrRateLimit {
+			t.Errorf("Expected %s, got %v", opentrivia.ErrRateLimit, err)
+		}
+
+		fake.SetResponseCode(-1)
+
+		if _, err := p.Random(nil); err != nil {
+			t.Errorf("Expected the refill to be tried again, got %s", err)
+		}
+	})
+
+	t.Run("expect invalid options to be rejected", func(t *testing.T) {
+		t.Parallel()
+
+		p, _ := newPrefetcher(t, 3)
+
+		if _, err := p.Random(&opentrivia.QuestionRandomOptions{Type: "open"}); err == nil {
+			t.Error("Expected a validation error")
+		}
+	})
+
+	t.Run("expect the context to stop the wait", func(t *testing.T) {
+		t.Parallel()
+
+		p, fake := newPrefetcher(t, 3)
+		fake.SetLatency(time.Second)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		if _, err := p.RandomContext(ctx, nil); err != context.DeadlineExceeded {
+			t.Errorf("Expected %s, got %v", context.DeadlineExceeded, err)
+		}
+	})
+}
+
+func TestPrefetcherClose(t *testing.T) {
+	t.Parallel()
+
+	p, fake := newPrefetcher(t, 3)
+	fake.SetLatency(time.Second)
+
+	waiting := make(chan error, 1)
+	go func() {
+		_, err := p.Random(nil)
+		waiting <- err
+	}()
+
+	// Let the refill start before closing.
+	time.Sleep(50 * time.Millisecond)
+
+	closed := make(chan error, 1)
+	go func() { closed <- p.Close() }()
+
+	select {
+	case err := <-closed:
+		if err != nil {
+			t.Errorf("Expected no error, got %s", err)
+		}
+	case <-time.After(900 * time.Millisecond):
+		t.Fatal("Expected Close to cancel the refills")
+	}
+
+	if err := <-waiting; err != prefetch.ErrClosed {
+		t.Errorf("Expected %s, got %v", prefetch.ErrClosed, err)
+	}
+
+	if err := p.Warm(prefetch.Key{}); err != prefetch.ErrClosed {
+		t.Errorf("Expected %s, got %v", prefetch.ErrClosed, err)
+	}
+	if err := p.Close(); err != nil {
+		t.Errorf("Expected Close to be idempotent, got %s", err)
+	}
+}
+
+func TestPrefetcherSmallPool(t *testing.T) {
+	t.Parallel()
+
+	p, fake := newPrefetcher(t, 10)
+	fake.SetQuestions([]opentrivia.Question{
+		opentriviatest.MultipleQuestion(opentrivia.QuestionCategoryArt, opentrivia.QuestionDifficultyHard, 1),
+		opentriviatest.MultipleQuestion(opentrivia.QuestionCategoryArt, opentrivia.QuestionDifficultyHard, 2),
+	})
+
+	key := prefetch.Key{Category: opentrivia.QuestionCategoryArt, Difficulty: opentrivia.QuestionDifficultyHard}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	seen := make(map[string]bool)
+	for i := 0; i < 2; i++ {
+		q, err := p.Next(ctx, key)
+		if err != nil {
+			t.Fatalf("Expected the questions of the key, got %s", err)
+		}
+
+		seen[q.Question] = true
+	}
+
+	if len(seen) != 2 {
+		t.Errorf("Expected the 2 questions of the key, got %d", len(seen))
+	}
+}
+
+func TestPrefetcherExpiredToken(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu  sync.Mutex
+		now = time.Now()
+	)
+
+	s := server.NewServer(newMemoryStore(t, opentriviatest.Questions()))
+	s.Now = func() time.Time {
+		mu.Lock()
+		defer mu.Unlock()
+
+		return now
+	}
+
+	ts := httptest.NewServer(s)
+	t.Cleanup(ts.Close)
+
+	c, err := opentrivia.NewClient(opentrivia.WithBaseURL(ts.URL + "/"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := prefetch.New(c, 2)
+	t.Cleanup(func() { p.Close() })
+
+	key := prefetch.Key{Category: opentrivia.QuestionCategoryHistory}
+	if err := p.Warm(key); err != nil {
+		t.Fatal(err)
+	}
+	waitReady(t, p, key, 2)
+
+	mu.Lock()
+	now = now.Add(7 * time.Hour)
+	mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// The pool is drained, so the next questions are fetched with the
+	// expired token.
+	for i := 0; i < 5; i++ {
+		if _, err := p.Next(ctx, key); err != nil {
+			t.Fatalf("Expected a new token to be created, got %s", err)
+		}
+	}
+}
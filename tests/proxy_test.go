@@ -0,0 +1,359 @@
+package tests
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pinheirolucas/opentrivia"
+	"github.com/pinheirolucas/opentrivia/opentriviatest"
+	"github.com/pinheirolucas/opentrivia/server"
+)
+
+// newProxyClient returns a client pointed at a proxy to a fake upstream.
+func newProxyClient(t *testing.T, interval time.Duration) (*opentrivia.Client, *opentriviatest.Server) {
+	upstream := opentriviatest.NewServer()
+	t.Cleanup(upstream.Close)
+
+	proxy := httptest.NewServer(server.NewProxy(upstream.Client(), interval))
+	t.Cleanup(proxy.Close)
+
+	c, err := opentrivia.NewClient(opentrivia.WithBaseURL(proxy.URL + "/"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return c, upstream
+}
+
+func TestProxyCache(t *testing.T) {
+	t.Parallel()
+
+	c, upstream := newProxyClient(t, 0)
+
+	options := &opentrivia.QuestionListOptions{
+		Category: opentrivia.QuestionCategoryHistory,
+		Limit:    10,
+	}
+
+	for i := 0; i < 3; i++ {
+		list, err := c.Question.List(options)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(list) != 10 {
+			t.Fatalf("Expected 10 questions, got %d", len(list))
+		}
+	}
+
+	if requests := upstream.Requests(); requests != 1 {
+		t.Errorf("Expected the pool to be fetched once, got %d upstream requests", requests)
+	}
+}
+
+func TestProxyTokens(t *testing.T) {
+	t.Parallel()
+
+	c, upstream := newProxyClient(t, 0)
+
+	var questions []opentrivia.Question
+	for i := 0; i < 3; i++ {
+		questions = append(questions, opentriviatest.MultipleQuestion(opentrivia.QuestionCategoryArt, opentrivia.QuestionDifficultyEasy, i))
+	}
+	upstream.SetQuestions(questions)
+
+	token, err := c.Token.Create()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	seen := make(map[string]bool)
+	options := &opentrivia.QuestionListOptions{Limit: 1, Token: token}
+	for i := 0; i < 3; i++ {
+		list, err := c.Question.List(options)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if seen[list[0].Question] {
+			t.Errorf("The question %q was returned twice for the same token", list[0].Question)
+		}
+		seen[list[0].Question] = true
+	}
+
+	if _, err := c.Question.List(options); err != opentrivia.ErrTokenEmpty {
+		t.Errorf("Expected opentrivia.ErrTokenEmpty, got %v", err)
+	}
+
+	if _, err := c.Token.Refresh(token); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Question.List(options); err != nil {
+		t.Errorf("Expected the refreshed token to return questions, got %v", err)
+	}
+
+	if _, err := c.Question.List(&opentrivia.QuestionListOptions{Limit: 1, Token: "not_a_token"}); err != opentrivia.ErrTokenNotFound {
+		t.Errorf("Expected opentrivia.ErrTokenNotFound, got %v", err)
+	}
+}
+
+func TestProxyRateLimit(t *testing.T) {
+	t.Parallel()
+
+	const interval = 50 * time.Millisecond
+	c, _ := newProxyClient(t, interval)
+
+	start := time.Now()
+	for _, category := range []opentrivia.QuestionCategory{opentrivia.QuestionCategoryArt, opentrivia.QuestionCategoryMath, opentrivia.QuestionCategoryFilm} {
+		if _, err := c.Question.List(&opentrivia.QuestionListOptions{Category: category, Limit: 5}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if elapsed := time.Since(start); elapsed < 2*interval {
+		t.Errorf("Expected the upstream requests to be spaced by %s, took %s", interval, elapsed)
+	}
+}
+
+func TestProxyUnknownCategory(t *testing.T) {
+	t.Parallel()
+
+	c, upstream := newProxyClient(t, 0)
+
+	_, err := c.Question.List(&opentrivia.QuestionListOptions{
+		AllowUnknownCategory: true,
+		Category:             1,
+	})
+	if err != opentrivia.ErrNoResults {
+		t.Errorf("Expected opentrivia.ErrNoResults, got %v", err)
+	}
+
+	if requests := upstream.Requests(); requests != 2 {
+		t.Errorf("Expected 2 upstream requests, got %d", requests)
+	}
+}
+
+func TestProxyEncode(t *testing.T) {
+	t.Parallel()
+
+	upstream := opentriviatest.NewServer()
+	t.Cleanup(upstream.Close)
+	upstream.SetQuestions(curatedQuestions[:1])
+
+	proxy := httptest.NewServer(server.NewProxy(upstream.Client(), 0))
+	t.Cleanup(proxy.Close)
+
+	cases := map[string]string{
+		"":        "Which team owns the &quot;quiz&quot; service?",
+		"url3986": "Which%20team%20owns%20the%20%22quiz%22%20service%3F",
+		"base64":  base64.StdEncoding.EncodeToString([]byte(curatedQuestions[0].Question)),
+	}
+
+	for encode, expected := range cases {
+		body := getQuestions(t, proxy.URL+"/api.php?amount=1&encode="+encode)
+
+		if len(body.Results) != 1 || body.Results[0].Question != expected {
+			t.Errorf("Expected %q for encode %q, got %+v", expected, encode, body)
+		}
+	}
+
+	t.Run("expect an unknown encoding to be rejected", func(t *testing.T) {
+		body := getQuestions(t, proxy.URL+"/api.php?amount=1&encode=rot13")
+
+		if body.ResponseCode != server.ResponseCodeInvalidParameter {
+			t.Errorf("Expected response code %d, got %d", server.ResponseCodeInvalidParameter, body.ResponseCode)
+		}
+	})
+}
+
+func TestProxyExpiry(t *testing.T) {
+	t.Parallel()
+
+	upstream := opentriviatest.NewServer()
+	t.Cleanup(upstream.Close)
+
+	now := time.Now()
+
+	proxy := server.NewProxy(upstream.Client(), 0)
+	proxy.TokenTTL = time.Hour
+	proxy.PoolTTL = 2 * time.Hour
+	proxy.Now = func() time.Time { return now }
+
+	ts := httptest.NewServer(proxy)
+	t.Cleanup(ts.Close)
+
+	c, err := opentrivia.NewClient(opentrivia.WithBaseURL(ts.URL + "/"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token, err := c.Token.Create()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := c.Token.Create(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := c.Question.List(&opentrivia.QuestionListOptions{Limit: 1, Token: token}); err != nil {
+		t.Fatal(err)
+	}
+
+	if tokens, pools := proxy.Size(); tokens != 3 || pools != 1 {
+		t.Fatalf("Expected 3 tokens and 1 pool, got %d and %d", tokens, pools)
+	}
+
+	now = now.Add(90 * time.Minute)
+
+	t.Run("expect the unused tokens to be deleted", func(t *testing.T) {
+		if _, err := c.Question.List(&opentrivia.QuestionListOptions{Limit: 1, Token: token}); err != opentrivia.ErrTokenNotFound {
+			t.Errorf("Expected opentrivia.ErrTokenNotFound, got %v", err)
+		}
+
+		if tokens, pools := proxy.Size(); tokens != 0 || pools != 1 {
+			t.Errorf("Expected no tokens and 1 pool, got %d and %d", tokens, pools)
+		}
+	})
+
+	now = now.Add(3 * time.Hour)
+
+	t.Run("expect the unused pools to be deleted", func(t *testing.T) {
+		if _, err := c.Category.List(); err != nil {
+			t.Fatal(err)
+		}
+
+		if tokens, pools := proxy.Size(); tokens != 0 || pools != 0 {
+			t.Errorf("Expected no tokens and no pools, got %d and %d", tokens, pools)
+		}
+	})
+}
+
+func TestProxyConcurrentSession(t *testing.T) {
+	t.Parallel()
+
+	c, upstream := newProxyClient(t, 0)
+	upstream.SetLatency(20 * time.Millisecond)
+
+	token, err := c.Token.Create()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const requests = 8
+
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		seen = make(map[string]bool)
+	)
+	for i := 0; i < requests; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			list, err := c.Question.List(&opentrivia.QuestionListOptions{Limit: 1, Token: token})
+			if err != nil {
+				t.Error(err)
+				return
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			if seen[list[0].Question] {
+				t.Errorf("The question %q was returned twice for the same token", list[0].Question)
+			}
+			seen[list[0].Question] = true
+		}()
+	}
+	wg.Wait()
+
+	// One request creates the upstream token and one fetches the pool.
+	if requests := upstream.Requests(); requests != 2 {
+		t.Errorf("Expected 2 upstream requests, got %d", requests)
+	}
+}
+
+func TestProxyExpiredUpstreamToken(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu  sync.Mutex
+		now = time.Now()
+	)
+
+	var questions []opentrivia.Question
+	for i := 0; i < 3; i++ {
+		questions = append(questions, opentriviatest.MultipleQuestion(opentrivia.QuestionCategoryArt, opentrivia.QuestionDifficultyEasy, i))
+	}
+
+	upstream := server.NewServer(newMemoryStore(t, questions))
+	upstream.Now = func() time.Time {
+		mu.Lock()
+		defer mu.Unlock()
+
+		return now
+	}
+
+	us := httptest.NewServer(upstream)
+	t.Cleanup(us.Close)
+
+	uc, err := opentrivia.NewClient(opentrivia.WithBaseURL(us.URL + "/"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ts := httptest.NewServer(server.NewProxy(uc, 0))
+	t.Cleanup(ts.Close)
+
+	c, err := opentrivia.NewClient(opentrivia.WithBaseURL(ts.URL + "/"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token, err := c.Token.Create()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	options := &opentrivia.QuestionListOptions{Limit: 1, Token: token}
+	if _, err := c.Question.List(options); err != nil {
+		t.Fatal(err)
+	}
+
+	mu.Lock()
+	now = now.Add(7 * time.Hour)
+	mu.Unlock()
+
+	// The pool has a single question, already seen, so the next one is
+	// fetched with the expired upstream token.
+	if _, err := c.Question.List(options); err != nil {
+		t.Errorf("Expected a new upstream token to be created, got %v", err)
+	}
+}
+
+type questionsBody struct {
+	ResponseCode int                   `json:"response_code"`
+	Results      []opentrivia.Question `json:"results"`
+}
+
+// getQuestions decodes the api.php response at the URL.
+func getQuestions(t *testing.T, url string) questionsBody {
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	var body questionsBody
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+
+	return body
+}
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/pinheirolucas/opentrivia"
+	"github.com/pkg/errors"
 )
 
 func TestQuestionIsAnswerCorrect(t *testing.T) {
@@ -67,6 +68,40 @@ func TestQuestionShuffleAnswers(t *testing.T) {
 	if correctCount != 1 {
 		t.Errorf("The list must have only one correct answer, got %d", correctCount)
 	}
+
+	t.Run("expect the incorrect answers to be kept", func(t *testing.T) {
+		incorrect := make([]string, 3, 4)
+		copy(incorrect, []string{"A", "B", "C"})
+		question := &opentrivia.Question{CorrectAnswer: "D", IncorrectAnswers: incorrect}
+
+		for i := 0; i < 10; i++ {
+			question.ShuffleAnswers()
+		}
+
+		if incorrect[0] != "A" || incorrect[1] != "B" || incorrect[2] != "C" || incorrect[:4][3] != "" {
+			t.Errorf("Expected the incorrect answers not to change, got %v", incorrect[:4])
+		}
+	})
+}
+
+func TestChooseAnswer(t *testing.T) {
+	t.Parallel()
+
+	answers := []string{"Rock &amp; Roll", "Jazz", "Blues"}
+
+	cases := map[string]string{
+		"b":           "Jazz",
+		" C ":         "Blues",
+		"rock & roll": "Rock &amp; Roll",
+		"D":           "D",
+		"Classical":   "Classical",
+	}
+
+	for input, expected := range cases {
+		if got := opentrivia.ChooseAnswer(answers, input); got != expected {
+			t.Errorf("Expected %s for %q, got %s", expected, input, got)
+		}
+	}
 }
 
 func TestQuestionServiceList(t *testing.T) {
@@ -252,7 +287,8 @@ func TestQuestionServiceList(t *testing.T) {
 		t.Parallel()
 
 		options := &opentrivia.QuestionListOptions{
-			Category: 1,
+			AllowUnknownCategory: true,
+			Category:             1,
 		}
 
 		_, err := client.Question.List(options)
@@ -269,7 +305,7 @@ func TestQuestionServiceList(t *testing.T) {
 		}
 
 		_, err := client.Question.List(options)
-		if err != opentrivia.ErrInvalidParameter {
+		if errors.Cause(err) != opentrivia.ErrInvalidParameter {
 			t.Error(err)
 		}
 	})
@@ -372,7 +408,8 @@ func TestQuestionServiceRandom(t *testing.T) {
 		t.Parallel()
 
 		options := &opentrivia.QuestionRandomOptions{
-			Category: 1,
+			AllowUnknownCategory: true,
+			Category:             1,
 		}
 
 		_, err := client.Question.Random(options)
@@ -389,7 +426,7 @@ func TestQuestionServiceRandom(t *testing.T) {
 		}
 
 		_, err := client.Question.Random(options)
-		if err != opentrivia.ErrInvalidParameter {
+		if errors.Cause(err) != opentrivia.ErrInvalidParameter {
 			t.Error(err)
 		}
 	})
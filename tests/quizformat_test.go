@@ -0,0 +1,173 @@
+package tests
+
+import (
+	"bytes"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/pinheirolucas/opentrivia"
+	"github.com/pinheirolucas/opentrivia/quizformat"
+)
+
+var exchangedQuestions = []opentrivia.Question{
+	{
+		Category:         "Science: Computers",
+		Type:             "multiple",
+		Difficulty:       "easy",
+		Question:         "Which key combination copies text: Ctrl+C or {Ctrl+V}?",
+		CorrectAnswer:    "Ctrl+C",
+		IncorrectAnswers: []string{"Ctrl+V", "Ctrl=X", "Ctrl#Z"},
+	},
+	{
+		Category:         "Science: Computers",
+		Type:             "boolean",
+		Difficulty:       "hard",
+		Question:         "The first computer bug was an actual bug.",
+		CorrectAnswer:    "True",
+		IncorrectAnswers: []string{"False"},
+	},
+	{
+		Category:         "History",
+		Type:             "multiple",
+		Difficulty:       "medium",
+		Question:         "In which year did the Berlin Wall fall?",
+		CorrectAnswer:    "1989",
+		IncorrectAnswers: []string{"1991", "1987", "1985"},
+	},
+}
+
+// sortedAnswers returns a copy of the questions with the incorrect answers
+// sorted, for formats that do not keep their order.
+func sortedAnswers(questions []opentrivia.Question) []opentrivia.Question {
+	sorted := make([]opentrivia.Question, len(questions))
+	for i, q := range questions {
+		q.IncorrectAnswers = append([]string{}, q.IncorrectAnswers...)
+		sort.Strings(q.IncorrectAnswers)
+		sorted[i] = q
+	}
+
+	return sorted
+}
+
+func TestQuizFormatRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	for _, name := range quizformat.Names() {
+		name := name
+
+		t.Run("expect "+name+" to round-trip", func(t *testing.T) {
+			t.Parallel()
+
+			f, err := quizformat.Lookup(name)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var buf bytes.Buffer
+			if err := f.Encode(&buf, exchangedQuestions); err != nil {
+				t.Fatal(err)
+			}
+
+			questions, err := f.Decode(&buf)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			expected := exchangedQuestions
+			if name == "kahoot" {
+				expected, questions = sortedAnswers(expected), sortedAnswers(questions)
+			}
+
+			if !reflect.DeepEqual(questions, expected) {
+				t.Errorf("Expected %+v, got %+v", expected, questions)
+			}
+		})
+	}
+}
+
+func TestDecodeGIFT(t *testing.T) {
+	t.Parallel()
+
+	const gift = `// Written by hand.
+$CATEGORY: Geography
+
+::Capital:: What is the capital of Brazil? {
+	=Brasília#Correct!
+	~%50%Rio de Janeiro
+	~São Paulo
+}
+
+Is the Amazon the largest rainforest?{T}
+`
+
+	questions, err := quizformat.DecodeGIFT(strings.NewReader(gift))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []opentrivia.Question{
+		{
+			Category:         "Geography",
+			Type:             "multiple",
+			Question:         "What is the capital of Brazil?",
+			CorrectAnswer:    "Brasília",
+			IncorrectAnswers: []string{"Rio de Janeiro", "São Paulo"},
+		},
+		{
+			Category:         "Geography",
+			Type:             "boolean",
+			Question:         "Is the Amazon the largest rainforest?",
+			CorrectAnswer:    "True",
+			IncorrectAnswers: []string{"False"},
+		},
+	}
+
+	if !reflect.DeepEqual(questions, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, questions)
+	}
+}
+
+func TestDecodeCSV(t *testing.T) {
+	t.Parallel()
+
+	t.Run("expect the columns to be matched by the header", func(t *testing.T) {
+		t.Parallel()
+
+		const data = "question,correct_answer,incorrect_answer_1,incorrect_answer_2\nIs water wet?,True,False,\n"
+
+		questions, err := quizformat.DecodeCSV(strings.NewReader(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(questions) != 1 || questions[0].Type != "boolean" || len(questions[0].IncorrectAnswers) != 1 {
+			t.Errorf("Unexpected questions %+v", questions)
+		}
+	})
+
+	t.Run("expect an error for a missing column", func(t *testing.T) {
+		t.Parallel()
+
+		if _, err := quizformat.DecodeCSV(strings.NewReader("question\nWhy?\n")); err == nil {
+			t.Error("Expected an error for a missing correct_answer column")
+		}
+	})
+}
+
+func TestEncodeKahoot(t *testing.T) {
+	t.Parallel()
+
+	questions := []opentrivia.Question{
+		{
+			Question:         "Too many answers?",
+			CorrectAnswer:    "Yes",
+			IncorrectAnswers: []string{"No", "Maybe", "Perhaps", "Never"},
+		},
+	}
+
+	if err := quizformat.EncodeKahoot(&bytes.Buffer{}, questions); err == nil {
+		t.Error("Expected an error for a question with five answers")
+	}
+}
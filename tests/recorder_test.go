@@ -0,0 +1,95 @@
+package tests
+
+import (
+	"net/http"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/pinheirolucas/opentrivia"
+	"github.com/pinheirolucas/opentrivia/opentriviatest"
+)
+
+func newRecordingClient(t *testing.T, baseURL string, path string, mode opentriviatest.Mode) *opentrivia.Client {
+	transport, err := opentriviatest.NewRecordingTransport(path, mode)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := opentrivia.NewClient(
+		opentrivia.WithHTTPClient(&http.Client{Transport: transport}),
+		opentrivia.WithBaseURL(baseURL),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return c
+}
+
+func TestRecordingTransport(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "cassette.json")
+
+	server := opentriviatest.NewServer()
+	baseURL := server.URL + "/"
+
+	recorder := newRecordingClient(t, baseURL, path, opentriviatest.ModeRecord)
+
+	token, err := recorder.Token.Create()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	options := &opentrivia.QuestionListOptions{Limit: 5, Token: token}
+	first, err := recorder.Question.List(options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := recorder.Question.List(options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The replay must not reach the network.
+	server.Close()
+
+	t.Run("expect the responses to be replayed in order", func(t *testing.T) {
+		t.Parallel()
+
+		replayer := newRecordingClient(t, baseURL, path, opentriviatest.ModeReplay)
+
+		options := &opentrivia.QuestionListOptions{Limit: 5, Token: "another_token"}
+
+		result, err := replayer.Question.List(options)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(result, first) {
+			t.Errorf("Expected %v, got %v", first, result)
+		}
+
+		result, err = replayer.Question.List(options)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(result, second) {
+			t.Errorf("Expected %v, got %v", second, result)
+		}
+
+		if _, err := replayer.Question.List(options); err == nil {
+			t.Error("Expected an error when the recorded interactions are over")
+		}
+	})
+
+	t.Run("expect an unrecorded request to fail", func(t *testing.T) {
+		t.Parallel()
+
+		replayer := newRecordingClient(t, baseURL, path, opentriviatest.ModeReplay)
+
+		if _, err := replayer.Category.List(); err == nil {
+			t.Error("Expected an error for an unrecorded request")
+		}
+	})
+}
@@ -0,0 +1,165 @@
+package tests
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"testing/quick"
+
+	"github.com/pinheirolucas/opentrivia"
+	"github.com/pinheirolucas/opentrivia/opentriviatest"
+)
+
+// codeServer is a fake Open Trivia API answering every request with the
+// configured response code and number of results.
+type codeServer struct {
+	*httptest.Server
+
+	mu      sync.Mutex
+	code    int
+	results int
+}
+
+func newCodeServer(t *testing.T) *codeServer {
+	s := &codeServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		code, n := s.code, s.results
+		s.mu.Unlock()
+
+		results := make([]opentrivia.Question, n)
+		for i := range results {
+			results[i] = opentriviatest.MultipleQuestion(opentrivia.QuestionCategoryComputer, opentrivia.QuestionDifficultyEasy, i)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"response_code": code,
+			"results":       results,
+			"token":         "token",
+		})
+	}))
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+func (s *codeServer) set(code, results int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.code = code
+	s.results = results
+}
+
+// expectedCodeError returns the error expected for a response code.
+func expectedCodeError(code int) error {
+	switch code {
+	case opentriviatest.ResponseCodeSuccess:
+		return nil
+	case opentriviatest.ResponseCodeNoResults:
+		return opentrivia.ErrNoResults
+	case opentriviatest.ResponseCodeInvalidParameter:
+		return opentrivia.ErrInvalidParameter
+	case opentriviatest.ResponseCodeTokenNotFound:
+		return opentrivia.ErrTokenNotFound
+	case opentriviatest.ResponseCodeTokenEmpty:
+		return opentrivia.ErrTokenEmpty
+	case opentriviatest.ResponseCodeRateLimit:
+		return opentrivia.ErrRateLimit
+	}
+
+	return &opentrivia.UnknownResponseCodeError{Code: code}
+}
+
+// sameCodeError reports if err is the error expected for a response code.
+func sameCodeError(err, expected error) bool {
+	var unknown *opentrivia.UnknownResponseCodeError
+	if errors.As(expected, &unknown) {
+		var got *opentrivia.UnknownResponseCodeError
+		return errors.As(err, &got) && got.Code == unknown.Code
+	}
+
+	return err == expected
+}
+
+func TestResponseCodes(t *testing.T) {
+	t.Parallel()
+
+	s := newCodeServer(t)
+
+	c, err := opentrivia.NewClient(opentrivia.WithBaseURL(s.URL + "/"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Every documented response code, and a few unknown ones.
+	codes := []int{0, 1, 2, 3, 4, 5, 6, 42, 255}
+
+	for _, code := range codes {
+		for _, results := range []int{0, 1, 3} {
+			s.set(code, results)
+			expected := expectedCodeError(code)
+
+			q, err := c.Question.Random(nil)
+			if code == opentriviatest.ResponseCodeSuccess && results == 0 {
+				if err != opentrivia.ErrNoResults {
+					t.Errorf("Random: expected %s for an empty success, got %v", opentrivia.ErrNoResults, err)
+				}
+			} else if !sameCodeError(err, expected) {
+				t.Errorf("Random: expected %v for code %d and %d results, got %v", expected, code, results, err)
+			}
+			if err != nil && q.Question != "" {
+				t.Errorf("Random: expected no question with an error, got %q", q.Question)
+			}
+
+			list, err := c.Question.List(nil)
+			if !sameCodeError(err, expected) {
+				t.Errorf("List: expected %v for code %d and %d results, got %v", expected, code, results, err)
+			}
+			if list == nil || (err == nil && len(list) != results) {
+				t.Errorf("List: expected %d questions, got %v", results, list)
+			}
+
+			token, err := c.Token.Create()
+			if !sameCodeError(err, expected) || (err != nil && token != "") {
+				t.Errorf("Create: expected %v for code %d, got %q and %v", expected, code, token, err)
+			}
+		}
+	}
+}
+
+func TestRandomNeverPanics(t *testing.T) {
+	t.Parallel()
+
+	s := newCodeServer(t)
+
+	c, err := opentrivia.NewClient(opentrivia.WithBaseURL(s.URL + "/"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Random returns a question if and only if the response is a success
+	// holding results, and the error of the response code otherwise.
+	property := func(code uint8, results uint8) bool {
+		n := int(results % 4)
+		s.set(int(code), n)
+
+		q, err := c.Question.Random(nil)
+		if code == 0 && n > 0 {
+			return err == nil && q.Question != ""
+		}
+		if code == 0 {
+			return err == opentrivia.ErrNoResults
+		}
+
+		return sameCodeError(err, expectedCodeError(int(code)))
+	}
+
+	if err := quick.Check(property, &quick.Config{MaxCount: 200}); err != nil {
+		t.Error(err)
+	}
+}
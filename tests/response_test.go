@@ -0,0 +1,93 @@
+package tests
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/pinheirolucas/opentrivia"
+)
+
+// newRawClient returns a client pointed at a server answering every request
+// with the provided status, content type and body.
+func newRawClient(t *testing.T, status int, contentType, body string, opts ...opentrivia.Option) *opentrivia.Client {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", contentType)
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(ts.Close)
+
+	c, err := opentrivia.NewClient(append(opts, opentrivia.WithBaseURL(ts.URL+"/"))...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return c
+}
+
+func TestClientResponseErrors(t *testing.T) {
+	t.Parallel()
+
+	page := "<html><body>" + strings.Repeat("Service Unavailable ", 100) + "</body></html>"
+
+	cases := []struct {
+		name        string
+		status      int
+		contentType string
+		body        string
+		opts        []opentrivia.Option
+		reason      string
+	}{
+		{"expect the status to be checked", http.StatusServiceUnavailable, "text/html", page, nil, "unexpected status 503"},
+		{"expect the content type to be checked", http.StatusOK, "text/html; charset=utf-8", page, nil, "unexpected content type"},
+		{"expect the body size to be limited", http.StatusOK, "application/json", `{"response_code":0,"results":[]}`, []opentrivia.Option{opentrivia.WithMaxResponseSize(10)}, "exceeds 10 bytes"},
+		{"expect the data after the value to be rejected", http.StatusOK, "application/json", `{"response_code":0,"results":[]}<html>`, nil, "error decoding the body"},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			c := newRawClient(t, tc.status, tc.contentType, tc.body, tc.opts...)
+
+			_, err := c.Question.List(&opentrivia.QuestionListOptions{Token: "secret"})
+
+			var respErr *opentrivia.ResponseError
+			if !errors.As(err, &respErr) {
+				t.Fatalf("Expected *opentrivia.ResponseError, got %v", err)
+			}
+
+			if !strings.Contains(respErr.Reason, tc.reason) {
+				t.Errorf("Expected %q in the reason, got %q", tc.reason, respErr.Reason)
+			}
+			if respErr.StatusCode != tc.status {
+				t.Errorf("Expected %d, got %d", tc.status, respErr.StatusCode)
+			}
+			if len(respErr.Body) > 515 || !strings.HasPrefix(tc.body, strings.TrimSuffix(respErr.Body, "...")) {
+				t.Errorf("Expected a snippet of the body, got %q", respErr.Body)
+			}
+			if strings.Contains(err.Error(), "secret") {
+				t.Errorf("Expected the token to be redacted, got %s", err)
+			}
+		})
+	}
+}
+
+func TestClientResponseContentTypes(t *testing.T) {
+	t.Parallel()
+
+	body := `{"response_code":0,"results":[{"question":"Q"}]}`
+
+	for _, contentType := range []string{"application/json", "application/json; charset=utf-8", "application/vnd.api+json", ""} {
+		c := newRawClient(t, http.StatusOK, contentType, body)
+
+		if _, err := c.Question.Random(nil); err != nil {
+			t.Errorf("Expected %q to be accepted, got %s", contentType, err)
+		}
+	}
+}
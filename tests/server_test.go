@@ -0,0 +1,265 @@
+package tests
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/pinheirolucas/opentrivia"
+	"github.com/pinheirolucas/opentrivia/server"
+)
+
+var curatedQuestions = []opentrivia.Question{
+	{
+		Category:         "Company Trivia",
+		Type:             string(opentrivia.QuestionTypeMultiple),
+		Difficulty:       string(opentrivia.QuestionDifficultyEasy),
+		Question:         "Which team owns the \"quiz\" service?",
+		CorrectAnswer:    "Platform",
+		IncorrectAnswers: []string{"Mobile", "Data", "Growth"},
+	},
+	{
+		Category:         opentrivia.QuestionCategoryHistory.Name(),
+		Type:             string(opentrivia.QuestionTypeTrueFalse),
+		Difficulty:       string(opentrivia.QuestionDifficultyHard),
+		Question:         "The company was founded in 2017.",
+		CorrectAnswer:    "True",
+		IncorrectAnswers: []string{"False"},
+	},
+}
+
+// newMemoryStore returns a server.MemoryStore holding the questions.
+func newMemoryStore(t testing.TB, questions []opentrivia.Question) *server.MemoryStore {
+	store, err := server.NewMemoryStore(questions)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return store
+}
+
+func newServerClient(t *testing.T, s *server.Server) *opentrivia.Client {
+	ts := httptest.NewServer(s)
+	t.Cleanup(ts.Close)
+
+	c, err := opentrivia.NewClient(opentrivia.WithBaseURL(ts.URL + "/"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return c
+}
+
+func TestServerCategories(t *testing.T) {
+	t.Parallel()
+
+	c := newServerClient(t, server.NewServer(newMemoryStore(t, curatedQuestions)))
+
+	categories, err := c.Category.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []opentrivia.Category{
+		{ID: opentrivia.QuestionCategoryHistory, Name: "History"},
+		{ID: 100, Name: "Company Trivia"},
+	}
+
+	if len(categories) != len(expected) || categories[0] != expected[0] || categories[1] != expected[1] {
+		t.Fatalf("Expected %v, got %v", expected, categories)
+	}
+
+	list, err := c.Question.List(&opentrivia.QuestionListOptions{
+		AllowUnknownCategory: true,
+		Category:             100,
+		Limit:                1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if list[0].CorrectAnswer != "Platform" {
+		t.Errorf("Expected the custom category question, got %+v", list[0])
+	}
+
+	count, err := c.Category.Count(opentrivia.QuestionCategoryHistory)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if count.Total != 1 || count.Hard != 1 {
+		t.Errorf("Unexpected count %+v", count)
+	}
+}
+
+func TestServerCategoryIDs(t *testing.T) {
+	t.Parallel()
+
+	var questions []opentrivia.Question
+	for i := 100; i <= 255; i++ {
+		questions = append(questions, opentrivia.Question{
+			Category: fmt.Sprintf("Custom %d", i),
+			Question: fmt.Sprintf("Question %d", i),
+		})
+	}
+
+	store := newMemoryStore(t, questions)
+
+	categories, err := store.Categories()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if last := categories[len(categories)-1]; last.ID != 255 || last.Name != "Custom 255" {
+		t.Fatalf("Expected the last category to be 255, got %v", last)
+	}
+
+	t.Run("expect known categories to be added", func(t *testing.T) {
+		if err := store.Add(opentrivia.Question{Category: opentrivia.QuestionCategoryHistory.Name()}); err != nil {
+			t.Errorf("Expected no error, got %s", err)
+		}
+	})
+
+	t.Run("expect the category past 255 to be rejected", func(t *testing.T) {
+		if err := store.Add(opentrivia.Question{Category: "Custom 256"}); err == nil {
+			t.Error("Expected an error, got nil")
+		}
+
+		questions, err := store.Questions(server.Filter{Category: "Custom 256"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(questions) != 0 {
+			t.Errorf("Expected the rejected question not to be added, got %v", questions)
+		}
+	})
+
+	t.Run("expect the store to reject too many categories", func(t *testing.T) {
+		questions := append(questions, opentrivia.Question{Category: "Custom 256"})
+		if _, err := server.NewMemoryStore(questions); err == nil {
+			t.Error("Expected an error, got nil")
+		}
+	})
+}
+
+func TestServerEncode(t *testing.T) {
+	t.Parallel()
+
+	ts := httptest.NewServer(server.NewServer(newMemoryStore(t, curatedQuestions[:1])))
+	defer ts.Close()
+
+	cases := map[string]string{
+		"":          "Which team owns the &quot;quiz&quot; service?",
+		"urlLegacy": "Which+team+owns+the+%22quiz%22+service%3F",
+		"url3986":   "Which%20team%20owns%20the%20%22quiz%22%20service%3F",
+		"base64":    base64.StdEncoding.EncodeToString([]byte(curatedQuestions[0].Question)),
+	}
+
+	for encode, expected := range cases {
+		resp, err := http.Get(ts.URL + "/api.php?amount=1&encode=" + encode)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var body struct {
+			ResponseCode int                   `json:"response_code"`
+			Results      []opentrivia.Question `json:"results"`
+		}
+		err = json.NewDecoder(resp.Body).Decode(&body)
+		resp.Body.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(body.Results) != 1 || body.Results[0].Question != expected {
+			t.Errorf("Expected %q for encode %q, got %+v", expected, encode, body)
+		}
+	}
+}
+
+func TestServerTokenTTL(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+
+	s := server.NewServer(newMemoryStore(t, curatedQuestions))
+	s.TokenTTL = time.Hour
+	s.Now = func() time.Time { return now }
+
+	c := newServerClient(t, s)
+
+	token, err := c.Token.Create()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	options := &opentrivia.QuestionListOptions{Limit: 2, Token: token}
+	if _, err := c.Question.List(options); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := c.Question.List(options); err != opentrivia.ErrTokenEmpty {
+		t.Errorf("Expected opentrivia.ErrTokenEmpty, got %v", err)
+	}
+
+	now = now.Add(2 * time.Hour)
+
+	if _, err := c.Question.List(options); err != opentrivia.ErrTokenNotFound {
+		t.Errorf("Expected the token to expire, got %v", err)
+	}
+}
+
+func TestServerRateLimit(t *testing.T) {
+	t.Parallel()
+
+	s := server.NewServer(newMemoryStore(t, curatedQuestions))
+	s.RateLimit = 1
+	s.RatePeriod = time.Hour
+
+	c := newServerClient(t, s)
+
+	if _, err := c.Token.Create(); err != nil {
+		t.Fatal(err)
+	}
+
+	if token, _ := c.Token.Create(); token != "" {
+		t.Errorf("Expected the second request to be rate limited, got token %s", token)
+	}
+}
+
+func TestServerSweep(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+
+	s := server.NewServer(newMemoryStore(t, curatedQuestions))
+	s.TokenTTL = time.Hour
+	s.RateLimit = 10
+	s.RatePeriod = time.Second
+	s.Now = func() time.Time { return now }
+
+	c := newServerClient(t, s)
+
+	for i := 0; i < 3; i++ {
+		if _, err := c.Token.Create(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if tokens, clients := s.Size(); tokens != 3 || clients != 1 {
+		t.Fatalf("Expected 3 tokens and 1 client, got %d and %d", tokens, clients)
+	}
+
+	now = now.Add(2 * time.Hour)
+
+	if _, err := c.Category.List(); err != nil {
+		t.Fatal(err)
+	}
+
+	if tokens, clients := s.Size(); tokens != 0 || clients != 0 {
+		t.Errorf("Expected the expired tokens and the idle clients to be deleted, got %d and %d", tokens, clients)
+	}
+}
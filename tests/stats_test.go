@@ -0,0 +1,229 @@
+package tests
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pinheirolucas/opentrivia"
+	"github.com/pinheirolucas/opentrivia/opentriviatest"
+	"github.com/pinheirolucas/opentrivia/stats"
+)
+
+func TestQuestionFingerprint(t *testing.T) {
+	t.Parallel()
+
+	q := opentriviatest.MultipleQuestion(opentrivia.QuestionCategoryComputer, opentrivia.QuestionDifficultyEasy, 1)
+
+	t.Run("expect the fingerprint to ignore the encoding", func(t *testing.T) {
+		encoded := q
+		encoded.Question = "easy multiple choice question #1 about Science: Computers&#039;?"
+		q := q
+		q.Question = "easy multiple choice question #1 about Science: Computers'?"
+
+		if q.Fingerprint() != encoded.Fingerprint() {
+			t.Error("Expected the same fingerprint")
+		}
+	})
+
+	t.Run("expect different questions to have different fingerprints", func(t *testing.T) {
+		other := opentriviatest.MultipleQuestion(opentrivia.QuestionCategoryComputer, opentrivia.QuestionDifficultyEasy, 2)
+
+		if q.Fingerprint() == other.Fingerprint() {
+			t.Error("Expected different fingerprints")
+		}
+	})
+}
+
+// monday is the Monday of the week used by the stats tests.
+var monday = time.Date(2017, 5, 1, 0, 0, 0, 0, time.UTC)
+
+func addStatsRecords(t *testing.T, s *stats.Stats) {
+	computer := opentriviatest.MultipleQuestion(opentrivia.QuestionCategoryComputer, opentrivia.QuestionDifficultyEasy, 1)
+	history := opentriviatest.MultipleQuestion(opentrivia.QuestionCategoryHistory, opentrivia.QuestionDifficultyHard, 1)
+
+	answers := []struct {
+		player   string
+		question *opentrivia.Question
+		answer   string
+		at       time.Time
+	}{
+		// Last week.
+		{"ana", &computer, "Correct", monday.Add(-time.Hour)},
+		{"ana", &computer, "Correct", monday.Add(-time.Hour)},
+		{"ana", &history, "Correct", monday.Add(-time.Hour)},
+		// This week.
+		{"ana", &history, "Incorrect 1", monday.Add(time.Hour)},
+		{"ana", &computer, "Correct", monday.Add(2 * time.Hour)},
+		{"bob", &computer, "Correct", monday.Add(time.Hour)},
+		{"bob", &history, "Correct", monday.Add(2 * time.Hour)},
+	}
+
+	for _, a := range answers {
+		r := stats.NewRecord(a.player, a.question, a.answer, 2*time.Second, a.at)
+		if err := s.Add(r); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestStatsPlayer(t *testing.T) {
+	t.Parallel()
+
+	s := stats.New(stats.NewMemoryStore())
+	addStatsRecords(t, s)
+
+	ana, err := s.Player("ana")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("expect the overall accuracy", func(t *testing.T) {
+		if ana.Answered != 5 || ana.Correct != 4 {
+			t.Errorf("Expected %d of %d, got %d of %d", 4, 5, ana.Correct, ana.Answered)
+		}
+		if ana.AverageTime != 2*time.Second {
+			t.Errorf("Expected %s, got %s", 2*time.Second, ana.AverageTime)
+		}
+	})
+
+	t.Run("expect the accuracy by category and difficulty", func(t *testing.T) {
+		history := ana.ByCategory[opentrivia.QuestionCategoryHistory]
+		if history.Rate() != 0.5 {
+			t.Errorf("Expected %f, got %f", 0.5, history.Rate())
+		}
+
+		easy := ana.ByDifficulty[opentrivia.QuestionDifficultyEasy]
+		if easy.Answered != 3 || easy.Correct != 3 {
+			t.Errorf("Expected %d of %d, got %d of %d", 3, 3, easy.Correct, easy.Answered)
+		}
+	})
+
+	t.Run("expect the streaks", func(t *testing.T) {
+		if ana.Streak != 1 || ana.BestStreak != 3 {
+			t.Errorf("Expected streaks %d and %d, got %d and %d", 1, 3, ana.Streak, ana.BestStreak)
+		}
+	})
+}
+
+func TestStatsLeaderboards(t *testing.T) {
+	t.Parallel()
+
+	s := stats.New(stats.NewMemoryStore())
+	s.Now = func() time.Time { return monday.Add(48 * time.Hour) }
+	addStatsRecords(t, s)
+
+	t.Run("expect the all-time leaderboard", func(t *testing.T) {
+		entries, err := s.AllTime(0)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(entries) != 2 || entries[0].PlayerID != "ana" || entries[0].Correct != 4 {
+			t.Errorf("Expected ana first with %d correct answers, got %+v", 4, entries)
+		}
+	})
+
+	t.Run("expect the weekly leaderboard", func(t *testing.T) {
+		entries, err := s.Weekly(1)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(entries) != 1 || entries[0].PlayerID != "bob" || entries[0].Rank != 1 {
+			t.Errorf("Expected bob first, got %+v", entries)
+		}
+	})
+
+	t.Run("expect the week to start on Monday", func(t *testing.T) {
+		sunday := monday.Add(-time.Minute)
+		if start := stats.WeekStart(sunday); !start.Equal(monday.Add(-7 * 24 * time.Hour)) {
+			t.Errorf("Expected the previous Monday, got %s", start)
+		}
+	})
+}
+
+func TestStatsFileStore(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "stats")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "stats.jsonl")
+
+	store, err := stats.OpenFileStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := stats.New(store)
+	addStatsRecords(t, s)
+	store.Close()
+
+	t.Run("expect the records to be reloaded", func(t *testing.T) {
+		store, err := stats.OpenFileStore(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer store.Close()
+
+		records, err := store.Records(stats.Filter{PlayerID: "bob"})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(records) != 2 {
+			t.Fatalf("Expected %d records, got %d", 2, len(records))
+		}
+		if records[1].Category != opentrivia.QuestionCategoryHistory || !records[1].Correct {
+			t.Errorf("Expected a correct history answer, got %+v", records[1])
+		}
+	})
+
+	t.Run("expect an empty difficulty to be reloaded", func(t *testing.T) {
+		path := filepath.Join(dir, "empty.jsonl")
+
+		store, err := stats.OpenFileStore(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Questions decoded from GIFT have no difficulty without a
+		// difficulty comment.
+		q := &opentrivia.Question{Category: "Custom", Question: "2 + 2?", CorrectAnswer: "4"}
+		if err := store.Add(stats.NewRecord("ana", q, "4", time.Second, time.Now())); err != nil {
+			t.Fatal(err)
+		}
+		store.Close()
+
+		store, err = stats.OpenFileStore(path)
+		if err != nil {
+			t.Fatalf("Expected the store to be reopened, got %s", err)
+		}
+		defer store.Close()
+
+		records, err := store.Records(stats.Filter{PlayerID: "ana"})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(records) != 1 || records[0].Difficulty != "" {
+			t.Errorf("Expected a record without difficulty, got %+v", records)
+		}
+
+		if _, err := stats.New(store).Player("ana"); err != nil {
+			t.Errorf("Expected the player stats, got %s", err)
+		}
+	})
+
+	t.Run("expect adding to a closed store to fail", func(t *testing.T) {
+		if err := store.Add(stats.Record{PlayerID: "ana"}); err == nil {
+			t.Error("Expected an error")
+		}
+	})
+}
@@ -0,0 +1,225 @@
+package tests
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pinheirolucas/opentrivia"
+	"github.com/pinheirolucas/opentrivia/opentriviatest"
+	"github.com/pinheirolucas/opentrivia/study"
+)
+
+func TestCardReview(t *testing.T) {
+	t.Parallel()
+
+	q := opentriviatest.MultipleQuestion(opentrivia.QuestionCategoryComputer, opentrivia.QuestionDifficultyEasy, 1)
+	at := time.Date(2017, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("expect the intervals to grow", func(t *testing.T) {
+		c := study.NewCard(q)
+
+		expected := []time.Duration{study.Day, 6 * study.Day, 15 * study.Day}
+		for i, interval := range expected {
+			c = c.Review(4, at)
+			if c.Interval != interval {
+				t.Errorf("Expected interval %s after review %d, got %s", interval, i+1, c.Interval)
+			}
+		}
+
+		if !c.Due.Equal(at.Add(15 * study.Day)) {
+			t.Errorf("Expected the card due in %s, got %s", 15*study.Day, c.Due)
+		}
+	})
+
+	t.Run("expect a failed review to start over", func(t *testing.T) {
+		c := study.NewCard(q).Review(5, at).Review(5, at).Review(1, at)
+
+		if c.Repetitions != 0 || c.Lapses != 1 || c.Interval != study.Day {
+			t.Errorf("Expected the card to start over, got %+v", c)
+		}
+	})
+
+	t.Run("expect the ease not to go below the minimum", func(t *testing.T) {
+		c := study.NewCard(q)
+		for i := 0; i < 10; i++ {
+			c = c.Review(0, at)
+		}
+
+		if c.Ease != study.MinEase {
+			t.Errorf("Expected %f, got %f", study.MinEase, c.Ease)
+		}
+	})
+}
+
+func TestScheduler(t *testing.T) {
+	t.Parallel()
+
+	server := opentriviatest.NewServer()
+	defer server.Close()
+
+	now := time.Date(2017, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	s := study.NewScheduler(server.Client().Question, study.NewMemoryStore())
+	s.Options = &opentrivia.QuestionListOptions{Type: opentrivia.QuestionTypeMultiple}
+	s.Now = func() time.Time { return now }
+
+	items, err := s.Next(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("expect fresh questions at first", func(t *testing.T) {
+		if len(items) != 3 {
+			t.Fatalf("Expected %d items, got %d", 3, len(items))
+		}
+
+		for _, item := range items {
+			if item.Review {
+				t.Error("Expected a fresh question")
+			}
+		}
+	})
+
+	if _, scheduled, _ := s.Answer(&items[0].Question, "Correct"); scheduled {
+		t.Error("Expected a correct fresh answer not to be scheduled")
+	}
+
+	missed := items[1].Question
+	c, scheduled, err := s.Answer(&missed, "Incorrect 1")
+	if err != nil || !scheduled {
+		t.Fatalf("Expected the missed question to be scheduled, got %v", err)
+	}
+
+	t.Run("expect the missed question to be due the next day", func(t *testing.T) {
+		if !c.Due.Equal(now.Add(study.Day)) {
+			t.Errorf("Expected %s, got %s", now.Add(study.Day), c.Due)
+		}
+
+		items, err := s.Next(3)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, item := range items {
+			if item.Review {
+				t.Error("Expected no review before the due date")
+			}
+		}
+	})
+
+	t.Run("expect the reviews to come first", func(t *testing.T) {
+		now = now.Add(study.Day)
+
+		items, err := s.Next(3)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(items) != 3 {
+			t.Fatalf("Expected %d items, got %d", 3, len(items))
+		}
+		if !items[0].Review || items[0].Question.Fingerprint() != missed.Fingerprint() {
+			t.Errorf("Expected the missed question first, got %+v", items[0])
+		}
+		if items[1].Review || items[2].Review {
+			t.Error("Expected fresh questions after the reviews")
+		}
+	})
+
+	t.Run("expect a correct review to push the card", func(t *testing.T) {
+		c, scheduled, err := s.Answer(&missed, "Correct")
+		if err != nil || !scheduled {
+			t.Fatalf("Expected the card to be rescheduled, got %v", err)
+		}
+
+		if c.Repetitions != 1 || !c.Due.Equal(now.Add(study.Day)) {
+			t.Errorf("Expected the card due in a day, got %+v", c)
+		}
+	})
+
+	t.Run("expect no questions when n is not positive", func(t *testing.T) {
+		for _, n := range []int{0, -1} {
+			items, err := s.Next(n)
+			if err != nil || len(items) != 0 {
+				t.Errorf("Expected no questions for %d, got %+v and %v", n, items, err)
+			}
+		}
+	})
+
+	t.Run("expect only the reviews when there are no fresh questions", func(t *testing.T) {
+		server.SetQuestions([]opentrivia.Question{})
+		now = now.Add(study.Day)
+
+		items, err := s.Next(3)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(items) != 1 || !items[0].Review {
+			t.Errorf("Expected a single review, got %+v", items)
+		}
+	})
+}
+
+func TestStudyFileStore(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "study")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "cards.jsonl")
+	now := time.Date(2017, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	store, err := study.OpenFileStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	q := opentriviatest.MultipleQuestion(opentrivia.QuestionCategoryArt, opentrivia.QuestionDifficultyEasy, 1)
+	c := study.NewCard(q).Review(1, now)
+	if err := store.Put(c); err != nil {
+		t.Fatal(err)
+	}
+
+	c = c.Review(1, now.Add(study.Day))
+	if err := store.Put(c); err != nil {
+		t.Fatal(err)
+	}
+	store.Close()
+
+	t.Run("expect the last card to be reloaded", func(t *testing.T) {
+		store, err := study.OpenFileStore(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer store.Close()
+
+		reloaded, ok, err := store.Get(q.Fingerprint())
+		if err != nil || !ok {
+			t.Fatalf("Expected the card to be found, got %v", err)
+		}
+		if reloaded.Lapses != 2 || !reloaded.Due.Equal(c.Due) {
+			t.Errorf("Expected %+v, got %+v", c, reloaded)
+		}
+
+		due, err := store.Due(c.Due)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(due) != 1 {
+			t.Errorf("Expected 1 due card, got %d", len(due))
+		}
+	})
+
+	t.Run("expect closed stores to fail", func(t *testing.T) {
+		if err := store.Put(c); err == nil {
+			t.Error("Expected an error, got nil")
+		}
+	})
+}
@@ -0,0 +1,156 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/pinheirolucas/opentrivia"
+	"github.com/pinheirolucas/opentrivia/opentriviatest"
+)
+
+func newTracedClient(t *testing.T) (*opentrivia.Client, *opentriviatest.Server, *opentriviatest.SpanRecorder) {
+	fake := opentriviatest.NewServer()
+	t.Cleanup(fake.Close)
+
+	recorder := opentriviatest.NewSpanRecorder()
+	c := fake.Client()
+	c.Tracer = recorder
+
+	return c, fake, recorder
+}
+
+func TestTracerSpans(t *testing.T) {
+	t.Parallel()
+
+	c, _, recorder := newTracedClient(t)
+
+	ctx, parent := recorder.Start(context.Background(), "round")
+
+	options := &opentrivia.QuestionListOptions{
+		Category:   opentrivia.QuestionCategoryHistory,
+		Difficulty: opentrivia.QuestionDifficultyHard,
+		Limit:      5,
+	}
+	if _, err := c.Question.ListContext(ctx, options); err != nil {
+		t.Fatal(err)
+	}
+
+	spans := recorder.Spans()
+	if len(spans) != 2 {
+		t.Fatalf("Expected %d spans, got %d", 2, len(spans))
+	}
+
+	list := spans[1]
+
+	t.Run("expect the span to be a child of the span of the context", func(t *testing.T) {
+		if list.Name != opentrivia.SpanQuestionList {
+			t.Errorf("Expected %s, got %s", opentrivia.SpanQuestionList, list.Name)
+		}
+		if list.Parent != parent {
+			t.Errorf("Expected the parent to be %v, got %v", parent, list.Parent)
+		}
+		if !list.Ended() {
+			t.Error("Expected the span to be ended")
+		}
+	})
+
+	t.Run("expect the attributes of the request", func(t *testing.T) {
+		expected := map[string]interface{}{
+			"opentrivia.category":      int(opentrivia.QuestionCategoryHistory),
+			"opentrivia.difficulty":    "hard",
+			"opentrivia.amount":        5,
+			"opentrivia.response_code": 0,
+		}
+
+		for key, value := range expected {
+			if v := list.Attribute(key); v != value {
+				t.Errorf("Expected %s to be %v, got %v", key, value, v)
+			}
+		}
+	})
+}
+
+func TestTracerAutoRefresh(t *testing.T) {
+	t.Parallel()
+
+	c, fake, recorder := newTracedClient(t)
+
+	fake.SetQuestions([]opentrivia.Question{
+		opentriviatest.MultipleQuestion(opentrivia.QuestionCategoryComputer, opentrivia.QuestionDifficultyEasy, 1),
+	})
+
+	token, err := c.Token.Create()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	options := &opentrivia.QuestionRandomOptions{Token: token, AutoRefresh: true}
+	if _, err := c.Question.Random(options); err != nil {
+		t.Fatal(err)
+	}
+
+	recorder.Reset()
+	if _, err := c.Question.Random(options); err != nil {
+		t.Fatal(err)
+	}
+
+	spans := recorder.Spans()
+	if len(spans) != 4 {
+		t.Fatalf("Expected %d spans, got %d", 4, len(spans))
+	}
+
+	random, refresh, retry, retried := spans[0], spans[1], spans[2], spans[3]
+
+	if random.Name != opentrivia.SpanQuestionRandom || random.Parent != nil {
+		t.Errorf("Expected a root %s span, got %s", opentrivia.SpanQuestionRandom, random.Name)
+	}
+	if random.Attribute("opentrivia.response_code") != 4 {
+		t.Errorf("Expected the response code %d, got %v", 4, random.Attribute("opentrivia.response_code"))
+	}
+	if refresh.Name != opentrivia.SpanTokenRefresh || refresh.Parent != random || refresh.Attribute("opentrivia.auto") != true {
+		t.Errorf("Expected an automatic %s child span, got %s", opentrivia.SpanTokenRefresh, refresh.Name)
+	}
+	if retry.Name != opentrivia.SpanRetry || retry.Parent != random {
+		t.Errorf("Expected a %s child span, got %s", opentrivia.SpanRetry, retry.Name)
+	}
+	if retried.Name != opentrivia.SpanQuestionRandom || retried.Parent != retry {
+		t.Errorf("Expected the retried %s span, got %s", opentrivia.SpanQuestionRandom, retried.Name)
+	}
+
+	for _, s := range spans {
+		if !s.Ended() || len(s.Errors()) != 0 {
+			t.Errorf("Expected %s to end without errors, got %v", s.Name, s.Errors())
+		}
+	}
+}
+
+func TestTracerErrors(t *testing.T) {
+	t.Parallel()
+
+	c, fake, recorder := newTracedClient(t)
+
+	t.Run("expect the API errors to be recorded", func(t *testing.T) {
+		fake.SetResponseCode(opentriviatest.ResponseCodeNoResults)
+		defer fake.SetResponseCode(-1)
+
+		if _, err := c.Question.Random(nil); err != opentrivia.ErrNoResults {
+			t.Fatalf("Expected %s, got %v", opentrivia.ErrNoResults, err)
+		}
+
+		spans := recorder.Spans()
+		errs := spans[len(spans)-1].Errors()
+		if len(errs) != 1 || errs[0] != opentrivia.ErrNoResults {
+			t.Errorf("Expected %s, got %v", opentrivia.ErrNoResults, errs)
+		}
+	})
+
+	t.Run("expect the context to cancel the request", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		if _, err := c.Token.CreateContext(ctx); !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected %s, got %v", context.Canceled, err)
+		}
+	})
+}
@@ -0,0 +1,125 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/pinheirolucas/opentrivia"
+	"github.com/pkg/errors"
+)
+
+func TestQuestionListOptionsValidate(t *testing.T) {
+	t.Parallel()
+
+	t.Run("expect valid options to return no error", func(t *testing.T) {
+		t.Parallel()
+
+		options := &opentrivia.QuestionListOptions{
+			Category:   opentrivia.QuestionCategoryComputer,
+			Difficulty: opentrivia.QuestionDifficultyHard,
+			Limit:      10,
+			Type:       opentrivia.QuestionTypeTrueFalse,
+		}
+
+		if err := options.Validate(); err != nil {
+			t.Errorf("No errors expected, got: %s", err)
+		}
+	})
+
+	t.Run("expect an error for each invalid field", func(t *testing.T) {
+		t.Parallel()
+
+		options := &opentrivia.QuestionListOptions{
+			Category:   1,
+			Difficulty: "jasldjalkdkalsd",
+			Type:       "open",
+		}
+
+		err := options.Validate()
+
+		verr, ok := err.(*opentrivia.ValidationError)
+		if !ok {
+			t.Fatalf("Expected *opentrivia.ValidationError, got %T", err)
+		}
+
+		expectedFields := []string{"Category", "Difficulty", "Limit", "Type"}
+		if len(verr.Errors) != len(expectedFields) {
+			t.Fatalf("Expected %d field errors, got %d: %s", len(expectedFields), len(verr.Errors), err)
+		}
+
+		for i, field := range expectedFields {
+			if verr.Errors[i].Field != field {
+				t.Errorf("Expected field %s, got %s", field, verr.Errors[i].Field)
+			}
+		}
+	})
+
+	t.Run("expect the error cause to be opentrivia.ErrInvalidParameter", func(t *testing.T) {
+		t.Parallel()
+
+		options := &opentrivia.QuestionListOptions{Limit: 51}
+
+		if err := options.Validate(); errors.Cause(err) != opentrivia.ErrInvalidParameter {
+			t.Errorf("Expected opentrivia.ErrInvalidParameter, got %v", err)
+		}
+	})
+
+	t.Run("expect unknown categories to be allowed", func(t *testing.T) {
+		t.Parallel()
+
+		options := &opentrivia.QuestionListOptions{
+			AllowUnknownCategory: true,
+			Category:             200,
+			Limit:                10,
+		}
+
+		if err := options.Validate(); err != nil {
+			t.Errorf("No errors expected, got: %s", err)
+		}
+	})
+}
+
+func TestQuestionRandomOptionsValidate(t *testing.T) {
+	t.Parallel()
+
+	t.Run("expect empty options to be valid", func(t *testing.T) {
+		t.Parallel()
+
+		options := &opentrivia.QuestionRandomOptions{}
+
+		if err := options.Validate(); err != nil {
+			t.Errorf("No errors expected, got: %s", err)
+		}
+	})
+
+	t.Run("expect an invalid difficulty to be reported", func(t *testing.T) {
+		t.Parallel()
+
+		options := &opentrivia.QuestionRandomOptions{
+			Difficulty: "impossible",
+		}
+
+		err := options.Validate()
+
+		verr, ok := err.(*opentrivia.ValidationError)
+		if !ok {
+			t.Fatalf("Expected *opentrivia.ValidationError, got %T", err)
+		}
+
+		if len(verr.Errors) != 1 || verr.Errors[0].Field != "Difficulty" {
+			t.Errorf("Expected a single Difficulty error, got: %s", err)
+		}
+	})
+}
+
+func TestQuestionServiceValidation(t *testing.T) {
+	t.Parallel()
+
+	// The request must not reach the network.
+	c, _ := opentrivia.NewClient()
+	c.BaseURL = nil
+
+	_, err := c.Question.Random(&opentrivia.QuestionRandomOptions{Type: "open"})
+	if _, ok := err.(*opentrivia.ValidationError); !ok {
+		t.Errorf("Expected *opentrivia.ValidationError, got %v", err)
+	}
+}
@@ -1,7 +1,12 @@
 package opentrivia
 
-import "github.com/pkg/errors"
-import "github.com/google/go-querystring/query"
+import (
+	"context"
+	"strconv"
+
+	"github.com/google/go-querystring/query"
+	"github.com/pkg/errors"
+)
 
 type (
 	// Token is the type for tokens.
@@ -54,6 +59,15 @@ type TokenService service
 // If all questions for a given category has already been returned,
 // the request will return an opentrivia.ErrTokenEmpty.
 func (t *TokenService) Create() (Token, error) {
+	return t.CreateContext(context.Background())
+}
+
+// CreateContext is like Create, but sends the request with the context and
+// starts its span as a child of the span of the context.
+func (t *TokenService) CreateContext(ctx context.Context) (token Token, err error) {
+	ctx, span := t.client.tracer().Start(ctx, SpanTokenCreate)
+	defer func() { endSpan(span, err) }()
+
 	options := &tokenOptions{
 		Command: tokenCommandCreate,
 	}
@@ -69,10 +83,18 @@ func (t *TokenService) Create() (Token, error) {
 	}
 
 	var resp tokenResponse
-	if _, err := t.client.Do(req, &resp); err != nil {
+	if _, err := t.client.Do(req.WithContext(ctx), &resp); err != nil {
 		return "", err
 	}
 
+	span.SetAttribute("opentrivia.response_code", int(resp.ResponseCode))
+
+	if resp.ResponseCode != responseCodeSuccess {
+		return "", resp.ResponseCode.err()
+	}
+
+	t.client.metrics().Add(MetricTokenCreates, 1, nil)
+
 	return resp.Token, nil
 }
 
@@ -81,6 +103,22 @@ func (t *TokenService) Create() (Token, error) {
 // If the provided token is invalid, the request will return an
 // opentrivia.ErrTokenNotFound.
 func (t *TokenService) Refresh(token Token) (Token, error) {
+	return t.refresh(context.Background(), token, false)
+}
+
+// RefreshContext is like Refresh, but sends the request with the context
+// and starts its span as a child of the span of the context.
+func (t *TokenService) RefreshContext(ctx context.Context, token Token) (Token, error) {
+	return t.refresh(ctx, token, false)
+}
+
+// refresh refreshes the token. auto is true for the refreshes made by the
+// AutoRefresh option, which are told apart in the metrics and spans.
+func (t *TokenService) refresh(ctx context.Context, token Token, auto bool) (refreshed Token, err error) {
+	ctx, span := t.client.tracer().Start(ctx, SpanTokenRefresh)
+	span.SetAttribute("opentrivia.auto", auto)
+	defer func() { endSpan(span, err) }()
+
 	options := &tokenOptions{
 		Command: tokenCommandRefresh,
 		Token:   token,
@@ -97,16 +135,17 @@ func (t *TokenService) Refresh(token Token) (Token, error) {
 	}
 
 	var resp tokenResponse
-	if _, err := t.client.Do(req, &resp); err != nil {
+	if _, err := t.client.Do(req.WithContext(ctx), &resp); err != nil {
 		return "", err
 	}
 
-	switch resp.ResponseCode {
-	case responseCodeInvalidParameter:
-		return "", ErrInvalidParameter
-	case responseCodeTokenNotFound:
-		return "", ErrTokenNotFound
+	span.SetAttribute("opentrivia.response_code", int(resp.ResponseCode))
+
+	if resp.ResponseCode != responseCodeSuccess {
+		return "", resp.ResponseCode.err()
 	}
 
+	t.client.metrics().Add(MetricTokenRefreshes, 1, Labels{"auto": strconv.FormatBool(auto)})
+
 	return resp.Token, nil
 }
@@ -0,0 +1,76 @@
+package opentrivia
+
+import "context"
+
+// Names of the spans started by the Client.
+const (
+	SpanQuestionList      = "opentrivia.Question.List"
+	SpanQuestionListBatch = "opentrivia.Question.ListBatch"
+	SpanQuestionRandom    = "opentrivia.Question.Random"
+	SpanTokenCreate       = "opentrivia.Token.Create"
+	SpanTokenRefresh      = "opentrivia.Token.Refresh"
+
+	// SpanRetry wraps a request sent again, such as the request following
+	// an automatic token refresh.
+	SpanRetry = "opentrivia.retry"
+)
+
+// Tracer starts the spans of a Client. It is meant to be adapted to a
+// tracing system, such as OpenTelemetry.
+type Tracer interface {
+	// Start starts a span, child of the span carried by ctx if any, and
+	// returns a context carrying the new span.
+	Start(ctx context.Context, name string) (context.Context, Span)
+}
+
+// Span is an operation traced by a Tracer.
+type Span interface {
+	// SetAttribute sets an attribute of the span, such as its category.
+	SetAttribute(key string, value interface{})
+
+	// RecordError records an error of the operation.
+	RecordError(err error)
+
+	// End ends the span.
+	End()
+}
+
+// NopTracer is a Tracer that traces nothing.
+type NopTracer struct{}
+
+// Start implements the Tracer interface.
+func (NopTracer) Start(ctx context.Context, name string) (context.Context, Span) {
+	return ctx, nopSpan{}
+}
+
+type nopSpan struct{}
+
+func (nopSpan) SetAttribute(key string, value interface{}) {}
+func (nopSpan) RecordError(err error)                      {}
+func (nopSpan) End()                                       {}
+
+func (c *Client) tracer() Tracer {
+	if c.Tracer == nil {
+		return NopTracer{}
+	}
+
+	return c.Tracer
+}
+
+// endSpan records the error, if any, and ends the span.
+func endSpan(span Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+	}
+
+	span.End()
+}
+
+// setOptionAttributes sets the attributes of the options of a question
+// request.
+func setOptionAttributes(span Span, category QuestionCategory, difficulty QuestionDifficulty, kind QuestionType, amount uint8) {
+	span.SetAttribute("opentrivia.category", int(category))
+	span.SetAttribute("opentrivia.difficulty", string(difficulty))
+	span.SetAttribute("opentrivia.type", string(kind))
+	span.SetAttribute("opentrivia.amount", int(amount))
+}
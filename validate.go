@@ -0,0 +1,115 @@
+package opentrivia
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FieldError describes an option field with an invalid value.
+type FieldError struct {
+	Field  string
+	Value  interface{}
+	Reason string
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("invalid %s %q: %s", e.Field, fmt.Sprint(e.Value), e.Reason)
+}
+
+// ValidationError is returned when the provided options are not valid.
+// It holds an error for each invalid field.
+//
+// The cause of a ValidationError is opentrivia.ErrInvalidParameter, so
+// errors.Cause can be used to handle it as an invalid parameter returned
+// by the Open Trivia API.
+type ValidationError struct {
+	Errors []*FieldError
+}
+
+func (e *ValidationError) Error() string {
+	s := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		s[i] = err.Error()
+	}
+
+	return "opentrivia: the provided options are not valid: " + strings.Join(s, "; ")
+}
+
+// Cause returns opentrivia.ErrInvalidParameter.
+func (e *ValidationError) Cause() error {
+	return ErrInvalidParameter
+}
+
+type validator struct {
+	errors []*FieldError
+}
+
+func (v *validator) add(field string, value interface{}, reason string) {
+	v.errors = append(v.errors, &FieldError{
+		Field:  field,
+		Value:  value,
+		Reason: reason,
+	})
+}
+
+func (v *validator) category(c QuestionCategory, allowUnknown bool) {
+	if c == 0 || allowUnknown {
+		return
+	}
+	if _, ok := questionCategoryNames[c]; !ok {
+		v.add("Category", uint8(c), "unknown category, set AllowUnknownCategory to send it anyway")
+	}
+}
+
+func (v *validator) difficulty(d QuestionDifficulty) {
+	switch d {
+	case "", QuestionDifficultyEasy, QuestionDifficultyMedium, QuestionDifficultyHard:
+		return
+	}
+
+	v.add("Difficulty", d, "must be one of easy, medium or hard")
+}
+
+func (v *validator) questionType(t QuestionType) {
+	switch t {
+	case "", QuestionTypeMultiple, QuestionTypeTrueFalse:
+		return
+	}
+
+	v.add("Type", t, "must be one of multiple or boolean")
+}
+
+func (v *validator) err() error {
+	if len(v.errors) == 0 {
+		return nil
+	}
+
+	return &ValidationError{Errors: v.errors}
+}
+
+// Validate checks the options before they are sent to the Open Trivia API.
+// If any field is invalid, Validate returns an *opentrivia.ValidationError.
+func (o *QuestionListOptions) Validate() error {
+	var v validator
+
+	v.category(o.Category, o.AllowUnknownCategory)
+	v.difficulty(o.Difficulty)
+	if o.Limit == 0 || o.Limit > 50 {
+		v.add("Limit", o.Limit, "must be between 1 and 50")
+	}
+	v.questionType(o.Type)
+
+	return v.err()
+}
+
+// Validate checks the options before they are sent to the Open Trivia API.
+// If any field is invalid, Validate returns an *opentrivia.ValidationError.
+func (o *QuestionRandomOptions) Validate() error {
+	var v validator
+
+	v.category(o.Category, o.AllowUnknownCategory)
+	v.difficulty(o.Difficulty)
+	v.questionType(o.Type)
+
+	return v.err()
+}